@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jarmocluyse/ads-go/pkg/ads"
+	"github.com/jarmocluyse/ads-go/pkg/ads/fileservice"
+)
+
+// splitRemotePath splits a target path (e.g. C:\TwinCAT\3.1\Boot\app.log)
+// into its directory and file name.
+func splitRemotePath(remote string) (string, string) {
+	i := strings.LastIndexAny(remote, `\/`)
+	if i < 0 {
+		return "", remote
+	}
+	return remote[:i], remote[i+1:]
+}
+
+// handleLs lists a directory on the target.
+// Usage: ls <remote dir>
+func handleLs(args []string, client *ads.Client) {
+	if len(args) == 0 {
+		fmt.Println("[ERROR] Command 'ls': No directory provided (e.g. 'ls C:\\TwinCAT\\3.1\\Boot').")
+		return
+	}
+
+	entries, err := fileservice.New(client, args[0]).ReadDir(".")
+	if err != nil {
+		fmt.Printf("[ERROR] Command 'ls': Failed to list '%s': %v\n", args[0], err)
+		return
+	}
+
+	fmt.Printf("[OK] %d entries in '%s':\n", len(entries), args[0])
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if entry.IsDir() {
+			fmt.Printf("  %-40s %12s  %s\n", entry.Name()+"\\", "<DIR>", info.ModTime().Format("2006-01-02 15:04:05"))
+		} else {
+			fmt.Printf("  %-40s %12d  %s\n", entry.Name(), info.Size(), info.ModTime().Format("2006-01-02 15:04:05"))
+		}
+	}
+}
+
+// handleGet copies a file from the target to the local machine.
+// Usage: get <remote file> [local file]
+func handleGet(args []string, client *ads.Client) {
+	if len(args) == 0 {
+		fmt.Println("[ERROR] Command 'get': Usage: get <remote file> [local file]")
+		return
+	}
+
+	dir, name := splitRemotePath(args[0])
+	local := name
+	if len(args) > 1 {
+		local = args[1]
+	}
+
+	data, err := fileservice.New(client, dir).ReadFile(name)
+	if err != nil {
+		fmt.Printf("[ERROR] Command 'get': Failed to read '%s': %v\n", args[0], err)
+		return
+	}
+	if err := os.WriteFile(local, data, 0o644); err != nil {
+		fmt.Printf("[ERROR] Command 'get': Failed to write '%s': %v\n", local, err)
+		return
+	}
+	fmt.Printf("[OK] Copied '%s' to '%s' (%d bytes)\n", args[0], local, len(data))
+}
+
+// handlePut copies a local file to the target.
+// Usage: put <local file> <remote file>
+func handlePut(args []string, client *ads.Client) {
+	if len(args) < 2 {
+		fmt.Println("[ERROR] Command 'put': Usage: put <local file> <remote file>")
+		return
+	}
+
+	data, err := os.ReadFile(filepath.Clean(args[0]))
+	if err != nil {
+		fmt.Printf("[ERROR] Command 'put': Failed to read '%s': %v\n", args[0], err)
+		return
+	}
+
+	dir, name := splitRemotePath(args[1])
+	if err := fileservice.New(client, dir).WriteFile(name, data); err != nil {
+		fmt.Printf("[ERROR] Command 'put': Failed to write '%s': %v\n", args[1], err)
+		return
+	}
+	fmt.Printf("[OK] Copied '%s' to '%s' (%d bytes)\n", args[0], args[1], len(data))
+}
@@ -102,11 +102,6 @@ func handleListSubs(args []string, client *ads.Client) {
 
 	fmt.Println("[INFO] Active subscriptions:")
 	for id, sub := range subscriptions {
-		onChangeStr := "false"
-		if sub.Settings.SendOnChange {
-			onChangeStr = "true"
-		}
-
 		// Get path from symbol if available, otherwise show "raw"
 		path := "raw"
 		if sub.Symbol != nil {
@@ -114,8 +109,8 @@ func handleListSubs(args []string, client *ads.Client) {
 		}
 
 		fmt.Printf("  #%d: %s (port %d)\n", id, path, sub.Port)
-		fmt.Printf("      CycleTime: %dms | OnChange: %s\n",
-			sub.Settings.CycleTime.Milliseconds(), onChangeStr)
+		fmt.Printf("      CycleTime: %s | Mode: %s | State: %s\n",
+			sub.Settings.CycleTime, sub.Settings.EffectiveTransmissionMode(), sub.State())
 
 		// Add statistics if available
 		subscriptionStatsMutex.RLock()
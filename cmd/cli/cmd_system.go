@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strconv"
 	"time"
 
 	"github.com/jarmocluyse/ads-go/pkg/ads"
@@ -94,6 +95,91 @@ func handleSetState(args []string, client *ads.Client) {
 	}
 }
 
+// handlePlc starts, stops or resets a single PLC runtime.
+// Usage: plc <start|stop|reset> <port> [cold|origin]
+func handlePlc(args []string, client *ads.Client) {
+	if len(args) < 2 {
+		fmt.Println("[ERROR] Command 'plc': Usage: plc <start|stop|reset> <port> [cold|origin]")
+		return
+	}
+
+	port, err := strconv.ParseUint(args[1], 10, 16)
+	if err != nil {
+		fmt.Printf("[ERROR] Command 'plc': Invalid port '%s'.\n", args[1])
+		return
+	}
+
+	switch args[0] {
+	case "start":
+		if err := client.StartPlc(uint16(port)); err != nil {
+			fmt.Printf("[ERROR] Command 'plc': Failed to start PLC runtime on port %d: %v\n", port, err)
+			return
+		}
+		fmt.Printf("[OK] PLC runtime on port %d started.\n", port)
+	case "stop":
+		if err := client.StopPlc(uint16(port)); err != nil {
+			fmt.Printf("[ERROR] Command 'plc': Failed to stop PLC runtime on port %d: %v\n", port, err)
+			return
+		}
+		fmt.Printf("[OK] PLC runtime on port %d stopped.\n", port)
+	case "reset":
+		mode := "cold"
+		if len(args) > 2 {
+			mode = args[2]
+		}
+		switch mode {
+		case "cold":
+			err = client.ResetPlcCold(uint16(port))
+		case "origin":
+			err = client.ResetPlcOrigin(uint16(port))
+		default:
+			fmt.Printf("[ERROR] Command 'plc': Invalid reset mode '%s'. Use 'cold' or 'origin'.\n", mode)
+			return
+		}
+		if err != nil {
+			fmt.Printf("[ERROR] Command 'plc': Failed to reset (%s) PLC runtime on port %d: %v\n", mode, port, err)
+			return
+		}
+		fmt.Printf("[OK] PLC runtime on port %d reset (%s).\n", port, mode)
+	default:
+		fmt.Printf("[ERROR] Command 'plc': Invalid action '%s'. Use 'start', 'stop' or 'reset'.\n", args[0])
+	}
+}
+
+// handleRoutes lists or removes the AMS routes configured on the target.
+// Usage: routes <list|remove> [name]
+func handleRoutes(args []string, client *ads.Client) {
+	if len(args) == 0 {
+		fmt.Println("[ERROR] Command 'routes': Usage: routes <list|remove> [name]")
+		return
+	}
+
+	switch args[0] {
+	case "list":
+		routes, err := client.ReadRoutes()
+		if err != nil {
+			fmt.Printf("[ERROR] Command 'routes': Failed to read routes: %v\n", err)
+			return
+		}
+		fmt.Printf("[OK] %d routes configured:\n", len(routes))
+		for _, route := range routes {
+			fmt.Printf("  %-24s %-20s %-24s %-8s flags=0x%X\n", route.Name, route.NetID, route.Address, route.Transport, route.Flags)
+		}
+	case "remove":
+		if len(args) < 2 {
+			fmt.Println("[ERROR] Command 'routes': No route name provided (e.g. 'routes remove ENG-LAPTOP-07').")
+			return
+		}
+		if err := client.RemoveRoute(args[1]); err != nil {
+			fmt.Printf("[ERROR] Command 'routes': Failed to remove route '%s': %v\n", args[1], err)
+			return
+		}
+		fmt.Printf("[OK] Route '%s' removed.\n", args[1])
+	default:
+		fmt.Printf("[ERROR] Command 'routes': Invalid action '%s'. Use 'list' or 'remove'.\n", args[0])
+	}
+}
+
 // handleMonitor displays current TwinCAT state and information about background monitoring.
 // Usage: monitor
 func handleMonitor(args []string, client *ads.Client) {
@@ -19,6 +19,8 @@ func handleHelp(args []string, client *ads.Client) {
 	fmt.Println("  state_loop               - Continuously monitor TwinCAT state")
 	fmt.Println("  monitor                  - Monitor system notifications")
 	fmt.Println("  set_state <config|run>   - Switch TwinCAT state")
+	fmt.Println("  plc <start|stop|reset> <port> [cold|origin] - Control one PLC runtime")
+	fmt.Println("  routes <list|remove> [name] - List or remove routes on the target")
 
 	fmt.Println("\nRead Commands:")
 	fmt.Println("  read_value               - Read GLOBAL.gMyInt")
@@ -37,6 +39,11 @@ func handleHelp(args []string, client *ads.Client) {
 	fmt.Println("  read_raw                 - Read raw data by index/offset")
 	fmt.Println("  write_raw                - Write raw data by index/offset")
 
+	fmt.Println("\nFile Commands (target file system):")
+	fmt.Println("  ls <remote dir>                    - List a directory on the target")
+	fmt.Println("  get <remote file> [local file]     - Copy a file from the target")
+	fmt.Println("  put <local file> <remote file>     - Copy a file to the target")
+
 	fmt.Println("\nSubscription Commands:")
 	fmt.Println("  subscribe [path]         - Subscribe to variable changes (default: GLOBAL.gMyBoolToogle)")
 	fmt.Println("  list_subs                - List active subscriptions")
@@ -58,6 +58,15 @@ func Commandline(client *ads.Client) {
 			readline.PcItem("config"),
 			readline.PcItem("run"),
 		),
+		readline.PcItem("plc",
+			readline.PcItem("start"),
+			readline.PcItem("stop"),
+			readline.PcItem("reset"),
+		),
+		readline.PcItem("routes",
+			readline.PcItem("list"),
+			readline.PcItem("remove"),
+		),
 
 		// Read commands
 		readline.PcItem("read_value"),
@@ -81,6 +90,11 @@ func Commandline(client *ads.Client) {
 		readline.PcItem("read_raw"),
 		readline.PcItem("write_raw"),
 
+		// File commands
+		readline.PcItem("ls"),
+		readline.PcItem("get"),
+		readline.PcItem("put"),
+
 		// Subscription commands with variable path completions
 		readline.PcItem("subscribe",
 			readline.PcItem("GLOBAL.gMyIntCounter"),
@@ -20,6 +20,8 @@ func getHandlers() map[string]CommandHandler {
 		"state_loop":  handleStateLoop,
 		"monitor":     handleMonitor,
 		"set_state":   handleSetState,
+		"plc":         handlePlc,
+		"routes":      handleRoutes,
 
 		// Read commands (cmd_read.go)
 		"read_value":   handleReadValue,
@@ -38,6 +40,11 @@ func getHandlers() map[string]CommandHandler {
 		"read_raw":  handleReadRaw,
 		"write_raw": handleWriteRaw,
 
+		// File commands (cmd_files.go)
+		"ls":  handleLs,
+		"get": handleGet,
+		"put": handlePut,
+
 		// Subscription commands (cmd_subscriptions.go)
 		"subscribe":       handleSubscribe,
 		"list_subs":       handleListSubs,
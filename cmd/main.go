@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log/slog"
 	"os"
 	"sync"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/jarmocluyse/ads-go/cmd/cli"
 	"github.com/jarmocluyse/ads-go/pkg/ads"
+	adsserializer "github.com/jarmocluyse/ads-go/pkg/ads/ads-serializer"
 	adsstateinfo "github.com/jarmocluyse/ads-go/pkg/ads/ads-stateinfo"
 	"github.com/lmittmann/tint"
 )
@@ -28,6 +30,7 @@ func main() {
 	settings := ads.ClientSettings{
 		TargetNetID: defaultTargetNetID,
 		Timeout:     defaultTimeout,
+		EnumMode:    adsserializer.EnumModeName,
 	}
 
 	// Synchronization for reconnection logic
@@ -94,6 +97,10 @@ func main() {
 		}()
 	}
 
+	settings.OnStatusChange = func(client *ads.Client, newState, oldState ads.ConnectionState) {
+		slog.Info("EVENT: ADS connection state changed", "from", oldState.String(), "to", newState.String())
+	}
+
 	settings.OnStateChange = func(client *ads.Client, newState, oldState *adsstateinfo.SystemState) {
 		if oldState == nil {
 			// Initial state read
@@ -122,11 +129,13 @@ func main() {
 	}
 
 	defer func() {
-		slog.Info("main: Disconnecting from ADS router...")
-		if err := client.Disconnect(); err != nil {
-			slog.Error("main: Error during disconnect", "error", err)
+		slog.Info("main: Closing ADS client...")
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := client.Close(ctx); err != nil {
+			slog.Error("main: Error during close", "error", err)
 		}
-		slog.Info("main: Disconnected from ADS router.")
+		slog.Info("main: ADS client closed.")
 	}()
 	cli.Commandline(client)
 }
@@ -43,6 +43,13 @@
 //	    // Handle ADS protocol error
 //	}
 //
+// ADS errors are returned as *AdsError, which carries the numeric code.
+// ErrorCode extracts it from a (wrapped) error:
+//
+//	if code, ok := adserrors.ErrorCode(err); ok && code == 1808 {
+//	    // Symbol not found
+//	}
+//
 // # Error Code Reference
 //
 // The ADSError map contains the complete mapping of error codes to messages.
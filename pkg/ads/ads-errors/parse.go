@@ -11,6 +11,31 @@ var (
 	ErrInvalidLength = errors.New("invalid length received")
 )
 
+// AdsError is a non-zero ADS error code returned by the target, either in the
+// response payload or in the AMS header. It matches ErrAdsError with errors.Is.
+type AdsError struct {
+	Code uint32
+}
+
+// Error returns the description of the code, e.g. "ads error: Symbol not found".
+func (e *AdsError) Error() string {
+	return ErrAdsError.Error() + ": " + ErrorCodeToString(e.Code)
+}
+
+// Is reports whether target is ErrAdsError.
+func (e *AdsError) Is(target error) bool {
+	return target == ErrAdsError
+}
+
+// ErrorCode returns the ADS error code of err, if err wraps an AdsError.
+func ErrorCode(err error) (uint32, bool) {
+	var adsErr *AdsError
+	if errors.As(err, &adsErr) {
+		return adsErr.Code, true
+	}
+	return 0, false
+}
+
 // parse in bytes and check the ads error
 func CheckAdsError(bytes []byte) error {
 	if len(bytes) != 4 {
@@ -18,8 +43,7 @@ func CheckAdsError(bytes []byte) error {
 	}
 	errorCode := binary.LittleEndian.Uint32(bytes)
 	if errorCode != 0 {
-		code := ErrorCodeToString(errorCode)
-		return fmt.Errorf("%w: %s", ErrAdsError, code)
+		return &AdsError{Code: errorCode}
 	}
 	return nil
 
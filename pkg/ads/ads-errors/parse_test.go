@@ -1,6 +1,8 @@
 package adserrors_test
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/jarmocluyse/ads-go/pkg/ads/ads-errors"
@@ -149,3 +151,16 @@ func TestStripAdsError(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorCode(t *testing.T) {
+	err := adserrors.CheckAdsError([]byte{0x10, 0x07, 0x00, 0x00})
+	assert.ErrorIs(t, err, adserrors.ErrAdsError)
+	assert.Equal(t, "ads error: Symbol not found", err.Error())
+
+	code, ok := adserrors.ErrorCode(fmt.Errorf("ReadValue: %w", err))
+	assert.True(t, ok)
+	assert.Equal(t, uint32(1808), code)
+
+	_, ok = adserrors.ErrorCode(errors.New("timeout"))
+	assert.False(t, ok)
+}
@@ -10,7 +10,10 @@ import (
 // Deserialize converts binary data to a Go value according to the ADS data type.
 //
 // The function handles:
-//   - Primitive types (bool, int8-64, uint8-64, float32/64, strings)
+//   - Primitive types (bool, int8-64, uint8-64, float32/64)
+//   - STRING (UTF-8 or Windows-1252, see Options) and WSTRING (UTF-16LE)
+//   - IEC time types by type name (time.Duration or time.Time, see Options.LookupTimeType)
+//   - Enum types as integer, name or EnumValue (see Options.EnumMode)
 //   - Structs (returned as map[string]any)
 //   - Arrays (including multidimensional arrays)
 //
@@ -35,6 +38,21 @@ import (
 //	structMap := value.(map[string]any)
 //	field1 := structMap["Field1"].(int32)
 func Deserialize(data []byte, dataType types.AdsDataType, isArrayItem ...bool) (any, error) {
+	return DeserializeWithOptions(data, dataType, Options{}, isArrayItem...)
+}
+
+// DeserializeWithOptions is Deserialize with control over the string
+// encoding and how enum values are returned, see Options.
+//
+// Example:
+//
+//	// TwinCAT 2 target, STRING data is Windows-1252
+//	opts := adsserializer.Options{StringEncoding: adsserializer.StringEncodingWindows1252}
+//	value, err := adsserializer.DeserializeWithOptions(data, dataType, opts)
+//
+//	// Enum values as "E_State.Running" instead of 3
+//	value, err = adsserializer.DeserializeWithOptions(data, enumDataType, adsserializer.Options{EnumMode: adsserializer.EnumModeName})
+func DeserializeWithOptions(data []byte, dataType types.AdsDataType, opts Options, isArrayItem ...bool) (any, error) {
 	isArrItem := false
 	if len(isArrayItem) > 0 {
 		isArrItem = isArrayItem[0]
@@ -45,7 +63,7 @@ func Deserialize(data []byte, dataType types.AdsDataType, isArrayItem ...bool) (
 		result := make(map[string]any)
 		data = data[dataType.Offset:]
 		for _, subItem := range dataType.SubItems {
-			value, err := Deserialize(data, subItem)
+			value, err := DeserializeWithOptions(data, subItem, opts)
 			if err != nil {
 				return nil, err
 			}
@@ -67,7 +85,7 @@ func Deserialize(data []byte, dataType types.AdsDataType, isArrayItem ...bool) (
 					temp = append(temp, convertArrayDimension(dim+1))
 				} else {
 					// Final dimension - deserialize the actual element
-					value, err := Deserialize(data[dataPos:], dataType, true)
+					value, err := DeserializeWithOptions(data[dataPos:], dataType, opts, true)
 					if err != nil {
 						temp = append(temp, nil)
 					} else {
@@ -85,6 +103,24 @@ func Deserialize(data []byte, dataType types.AdsDataType, isArrayItem ...bool) (
 	// Apply offset for primitives (used in structs where each field has an offset)
 	data = data[dataType.Offset:]
 
+	// IEC time types are recognized by their type name
+	if timeType := opts.LookupTimeType(dataType.Type); timeType != TimeTypeNone {
+		return readTime(data, timeType)
+	}
+
+	// Enum types are read as their base type first
+	if isEnum(dataType) {
+		number, err := readPrimitive(data, dataType, opts)
+		if err != nil {
+			return nil, err
+		}
+		return readEnum(number, dataType, opts.EnumMode)
+	}
+	return readPrimitive(data, dataType, opts)
+}
+
+// readPrimitive reads a value of a primitive data type.
+func readPrimitive(data []byte, dataType types.AdsDataType, opts Options) (any, error) {
 	switch dataType.DataType {
 	case types.ADST_VOID:
 		return nil, nil
@@ -110,8 +146,10 @@ func Deserialize(data []byte, dataType types.AdsDataType, isArrayItem ...bool) (
 		return adsprimitives.ReadFloat32(data)
 	case types.ADST_REAL64:
 		return adsprimitives.ReadFloat64(data)
-	case types.ADST_STRING, types.ADST_WSTRING:
-		return adsprimitives.ReadString(data)
+	case types.ADST_STRING:
+		return readString(data, dataType.Size, opts.StringEncoding), nil
+	case types.ADST_WSTRING:
+		return readWstring(data, dataType.Size), nil
 	case types.ADST_BIGTYPE:
 		return nil, fmt.Errorf("ADST_BIGTYPE is not yet supported")
 	default:
@@ -11,9 +11,17 @@
 //   - ADST_INT8, ADST_INT16, ADST_INT32, ADST_INT64: signed integers
 //   - ADST_UINT8, ADST_UINT16, ADST_UINT32, ADST_UINT64: unsigned integers
 //   - ADST_REAL32, ADST_REAL64: floating-point numbers
-//   - ADST_STRING, ADST_WSTRING: strings (WSTRING uses UTF-16LE encoding)
+//   - ADST_STRING, ADST_WSTRING: strings (STRING is UTF-8 or Windows-1252, WSTRING is UTF-16LE)
 //   - ADST_VOID: no data
 //
+// Time types (recognized by the type name in AdsDataType.Type):
+//   - TIME, LTIME: time.Duration (milliseconds / nanoseconds)
+//   - TOD, LTOD: time.Duration since midnight (milliseconds / nanoseconds)
+//   - DATE, LDATE, DT, LDT: time.Time in UTC (seconds / nanoseconds since 1970-01-01)
+//
+// Enum types (data types with EnumInfo):
+//   - Integer of the base type, name string or EnumValue (see Options.EnumMode)
+//
 // Complex types:
 //   - Structs: Represented as map[string]any in Go
 //   - Arrays: Represented as []any in Go (supports multidimensional)
@@ -189,35 +197,89 @@
 //   - int can convert to float32/float64
 //   - float64 can convert to float32
 //
+// # Time Types
+//
+// IEC 61131-3 time types are stored as unsigned integers; the data type code
+// alone (ADST_UINT32, ADST_UINT64) cannot tell them apart from UDINT and ULINT.
+// They are therefore recognized by their type name, including the long forms
+// DATE_AND_TIME, TIME_OF_DAY, LDATE_AND_TIME and LTIME_OF_DAY:
+//
+//	dataType := types.AdsDataType{Type: "TIME", DataType: types.ADST_UINT32, Size: 4}
+//	value, err := adsserializer.Deserialize([]byte{0x88, 0x13, 0x00, 0x00}, dataType)
+//	timeout := value.(time.Duration) // 5s
+//
+//	data, err := adsserializer.Serialize(250*time.Millisecond, dataType)
+//
+// Date types carry no time zone on the PLC; they are read as UTC and a
+// time.Time is converted to UTC before it is written. DATE drops the time of
+// day, finer resolutions than the stored unit are truncated. Serialize still
+// accepts the plain integer for every time type.
+//
+// Alias types (TYPE T_Timeout : TIME; END_TYPE) are reported with their own
+// name. List them in Options.TimeAliases to convert them like their base type:
+//
+//	opts := adsserializer.Options{TimeAliases: map[string]adsserializer.TimeType{
+//	    "T_Timeout": adsserializer.TimeTypeTime,
+//	}}
+//	value, err := adsserializer.DeserializeWithOptions(data, dataType, opts)
+//
+// LDATE and LDT hold nanoseconds since 1970 and are limited to
+// 2262-04-11T23:47:16Z when written, the range of time.Time.UnixNano.
+//
+// # Enum Types
+//
+// Data types with EnumInfo are enums. They are read as the integer of their
+// base type unless Options.EnumMode asks for the name:
+//
+//	opts := adsserializer.Options{EnumMode: adsserializer.EnumModeName}
+//	value, err := adsserializer.DeserializeWithOptions(data, dataType, opts) // "E_State.Running"
+//
+// EnumModeValue returns an EnumValue with type, name and integer. A value the
+// enum does not define is read as the integer in every mode; the name modes
+// reject such integers when writing. Serialize accepts
+// integers, names ("Running" or "E_State.Running", not case sensitive) and
+// EnumValue for every mode.
+//
 // # String Handling
 //
 // ## ADST_STRING (Single-byte strings)
 //
-// Standard ADS strings are null-terminated and use single-byte encoding:
+// A STRING(n) occupies n+1 bytes, the declared size of the data type:
 //
 //	dataType := types.AdsDataType{
 //	    DataType: types.ADST_STRING,
-//	    Size:     80, // Buffer size in bytes
+//	    Size:     81, // STRING(80)
 //	}
 //
+// The data is UTF-8 on runtimes that set ADSUploadInfoFlagUtf8EncodedStringData
+// and Windows-1252 on older TwinCAT versions. Serialize and Deserialize use
+// UTF-8; pick the encoding with Options:
+//
+//	opts := adsserializer.Options{StringEncoding: adsserializer.StringEncodingWindows1252}
+//	data, err := adsserializer.SerializeWithOptions("Größe", dataType, opts)
+//
 // When serializing, the string is:
-//   - Truncated if longer than Size-1 (leaving room for null terminator)
-//   - Null-terminated
-//   - Padded with zeros to fill the buffer
+//   - Rejected if it needs more than Size-1 bytes (leaving room for the null
+//     terminator), or cut at a character boundary with Options.TruncateStrings
+//   - Rejected if it contains characters Windows-1252 cannot represent
+//   - Null-terminated and padded with zeros to fill the buffer
 //
-// When deserializing, the string is read until the null terminator.
+// When deserializing, the string is read until the null terminator, at most
+// Size bytes.
 //
 // ## ADST_WSTRING (Wide strings / UTF-16LE)
 //
-// Wide strings use UTF-16LE encoding (2 bytes per character):
+// Wide strings use UTF-16LE encoding (2 bytes per code unit, characters
+// outside the Basic Multilingual Plane take a surrogate pair):
 //
 //	dataType := types.AdsDataType{
 //	    DataType: types.ADST_WSTRING,
-//	    Size:     160, // Buffer size in bytes (80 characters * 2)
+//	    Size:     162, // WSTRING(80): (80 + 1) * 2
 //	}
 //
-// The Size field specifies bytes, not characters. For 80 characters, you need
-// Size = 160 bytes.
+// The Size field specifies bytes, not characters. The same length rules as
+// for STRING apply, counted in code units; truncation never splits a
+// surrogate pair.
 //
 // # Error Handling
 //
@@ -229,6 +291,8 @@
 //   - Invalid NetID format
 //   - Insufficient data during deserialization
 //   - Value out of range during type conversion
+//   - String longer than its declared size (unless Options.TruncateStrings)
+//   - Enum name, or integer in the name modes, that the enum does not define when serializing
 //
 // Example error handling:
 //
@@ -273,7 +337,7 @@
 //
 // # Thread Safety
 //
-// The Serialize and Deserialize functions are thread-safe as they don't
-// maintain any internal state. However, the dataType parameter is read-only
+// The Serialize and Deserialize functions are thread-safe. The only shared
+// state is the time alias registry, which is protected by a mutex. However, the dataType parameter is read-only
 // and should not be modified during serialization/deserialization.
 package adsserializer
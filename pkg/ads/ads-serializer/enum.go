@@ -0,0 +1,156 @@
+package adsserializer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+)
+
+// EnumMode selects how values of enum types (data types with EnumInfo) are
+// read.
+type EnumMode int
+
+const (
+	EnumModeNumber EnumMode = iota // the integer of the base type (default)
+	EnumModeName                   // the qualified name as a string, e.g. "E_State.Running"
+	EnumModeValue                  // an EnumValue with type, name and integer
+)
+
+// Values that the enum does not define are read as the integer of the base
+// type in every mode. Writing such an integer fails in the name modes.
+
+// String returns the name of the mode.
+func (m EnumMode) String() string {
+	switch m {
+	case EnumModeName:
+		return "Name"
+	case EnumModeValue:
+		return "Value"
+	default:
+		return "Number"
+	}
+}
+
+// EnumValue is a value of an enum type, read with EnumModeValue. Serialize
+// accepts it for enum types as well.
+type EnumValue struct {
+	Type  string // enum type name, e.g. E_State
+	Name  string // element name, e.g. Running
+	Value int64  // integer value
+}
+
+// String returns the qualified name, e.g. "E_State.Running".
+func (v EnumValue) String() string {
+	if v.Type == "" {
+		return v.Name
+	}
+	return v.Type + "." + v.Name
+}
+
+// isEnum reports whether dataType is an enum type.
+func isEnum(dataType types.AdsDataType) bool {
+	return len(dataType.EnumInfo) > 0
+}
+
+// readEnum converts the integer read for an enum type according to the mode.
+// Values that the enum does not define (e.g. an uninitialised variable or a
+// newer enum version) are returned as the integer in every mode, so they do
+// not fail the read of a whole structure.
+func readEnum(number any, dataType types.AdsDataType, mode EnumMode) (any, error) {
+	if mode == EnumModeNumber {
+		return number, nil
+	}
+	raw, ok := enumInteger(number)
+	if !ok {
+		return nil, fmt.Errorf("invalid value for enum %s: %T", dataType.Type, number)
+	}
+	for _, info := range dataType.EnumInfo {
+		if sameEnumValue(info.Value, raw, dataType.Size) {
+			value := EnumValue{Type: dataType.Type, Name: info.Name, Value: raw}
+			if mode == EnumModeName {
+				return value.String(), nil
+			}
+			return value, nil
+		}
+	}
+	return number, nil
+}
+
+// writeEnum resolves a name ("Running" or "E_State.Running"), an EnumValue or
+// an integer to the integer that is written for an enum type. Integers are
+// only checked against the defined values if the mode is not EnumModeNumber.
+func writeEnum(value any, dataType types.AdsDataType, mode EnumMode) (any, error) {
+	switch v := value.(type) {
+	case string:
+		name := v
+		if prefix := dataType.Type + "."; dataType.Type != "" && len(name) > len(prefix) && strings.EqualFold(name[:len(prefix)], prefix) {
+			name = name[len(prefix):]
+		}
+		for _, info := range dataType.EnumInfo {
+			if strings.EqualFold(info.Name, name) {
+				return enumNumber(info.Value, dataType), nil
+			}
+		}
+		return nil, fmt.Errorf("invalid value for enum %s: %q is not defined", dataType.Type, v)
+	case EnumValue:
+		if v.Name != "" {
+			return writeEnum(v.Name, dataType, mode)
+		}
+		return writeEnum(v.Value, dataType, EnumModeValue)
+	}
+
+	if mode == EnumModeNumber {
+		return value, nil
+	}
+	raw, ok := enumInteger(value)
+	if !ok {
+		return value, nil // reported by the integer conversion
+	}
+	for _, info := range dataType.EnumInfo {
+		if sameEnumValue(info.Value, raw, dataType.Size) {
+			return enumNumber(info.Value, dataType), nil
+		}
+	}
+	return nil, fmt.Errorf("invalid value for enum %s: %d is not defined", dataType.Type, raw)
+}
+
+// enumNumber returns a defined enum value as the int the integer conversion
+// expects. EnumInfo holds the value unsigned, signed base types need it sign
+// extended.
+func enumNumber(value int64, dataType types.AdsDataType) int {
+	switch dataType.DataType {
+	case types.ADST_INT8, types.ADST_INT16, types.ADST_INT32:
+		if dataType.Size == 0 || dataType.Size >= 8 {
+			return int(value)
+		}
+		shift := 64 - 8*dataType.Size
+		return int(value << shift >> shift)
+	default:
+		return int(value)
+	}
+}
+
+// sameEnumValue compares two enum values in the size of the base type, so an
+// unsigned EnumInfo value matches the signed value that was read.
+func sameEnumValue(a, b int64, size uint32) bool {
+	if size == 0 || size >= 8 {
+		return a == b
+	}
+	mask := uint64(1)<<(8*size) - 1
+	return uint64(a)&mask == uint64(b)&mask
+}
+
+// enumInteger converts any integer to int64.
+func enumInteger(value any) (int64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return int64(v.Uint()), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,15 @@
+package adsserializer
+
+// Options control how SerializeWithOptions and DeserializeWithOptions convert
+// values. The zero value is what Serialize and Deserialize use.
+type Options struct {
+	StringEncoding  StringEncoding // encoding of STRING data (WSTRING is always UTF-16LE)
+	TruncateStrings bool           // cut strings that exceed the declared size instead of failing
+	EnumMode        EnumMode       // how enum values are read; other modes also reject undefined integers on write
+
+	// TimeAliases maps alias type names (e.g. T_Timeout declared as
+	// "TYPE T_Timeout : TIME; END_TYPE") to the time type they convert like.
+	// The PLC reports variables of an alias type with the alias name, so they
+	// are plain integers unless listed. Names are not case sensitive.
+	TimeAliases map[string]TimeType
+}
@@ -11,7 +11,10 @@ import (
 // Serialize converts a Go value to binary data according to the ADS data type.
 //
 // The function handles:
-//   - Primitive types (bool, int8-64, uint8-64, float32/64, strings)
+//   - Primitive types (bool, int8-64, uint8-64, float32/64)
+//   - STRING (UTF-8 or Windows-1252, see Options) and WSTRING (UTF-16LE)
+//   - IEC time types by type name (time.Duration or time.Time, see Options.LookupTimeType)
+//   - Enum types by integer, name ("Running" or "E_State.Running") or EnumValue
 //   - Structs (expects map[string]any)
 //   - Arrays (including multidimensional arrays)
 //
@@ -37,6 +40,18 @@ import (
 //	}
 //	data, err := adsserializer.Serialize(structValue, structDataType)
 func Serialize(value any, dataType types.AdsDataType, isArrayItem ...bool) ([]byte, error) {
+	return SerializeWithOptions(value, dataType, Options{}, isArrayItem...)
+}
+
+// SerializeWithOptions is Serialize with control over the string encoding
+// and what happens to strings that exceed their declared size, see Options.
+//
+// Example:
+//
+//	// Cut texts that do not fit instead of failing
+//	opts := adsserializer.Options{TruncateStrings: true}
+//	data, err := adsserializer.SerializeWithOptions("Hello", dataType, opts)
+func SerializeWithOptions(value any, dataType types.AdsDataType, opts Options, isArrayItem ...bool) ([]byte, error) {
 	buf := new(bytes.Buffer)
 
 	isArrItem := false
@@ -56,7 +71,7 @@ func Serialize(value any, dataType types.AdsDataType, isArrayItem ...bool) ([]by
 			if !exists {
 				return nil, fmt.Errorf("missing field %s for struct", subItem.Name)
 			}
-			subItemBuf, err := Serialize(subItemValue, subItem)
+			subItemBuf, err := SerializeWithOptions(subItemValue, subItem, opts)
 			if err != nil {
 				return nil, err
 			}
@@ -96,7 +111,7 @@ func Serialize(value any, dataType types.AdsDataType, isArrayItem ...bool) ([]by
 					}
 				} else {
 					// Final dimension - serialize the element
-					elementBuf, err := Serialize(arr[i], dType, true)
+					elementBuf, err := SerializeWithOptions(arr[i], dType, opts, true)
 					if err != nil {
 						return err
 					}
@@ -111,6 +126,27 @@ func Serialize(value any, dataType types.AdsDataType, isArrayItem ...bool) ([]by
 		return buf.Bytes(), nil
 	}
 
+	// IEC time types accept time.Duration or time.Time, other values are
+	// written as the underlying integer
+	if timeType := opts.LookupTimeType(dataType.Type); timeType != TimeTypeNone {
+		data, ok, err := writeTime(value, timeType)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return data, nil
+		}
+	}
+
+	// Enum types accept names and EnumValue, written as the underlying integer
+	if isEnum(dataType) {
+		number, err := writeEnum(value, dataType, opts.EnumMode)
+		if err != nil {
+			return nil, err
+		}
+		value = number
+	}
+
 	// Handle primitive types last
 	switch dataType.DataType {
 	case types.ADST_VOID:
@@ -231,11 +267,10 @@ func Serialize(value any, dataType types.AdsDataType, isArrayItem ...bool) ([]by
 		if !ok {
 			return nil, fmt.Errorf("invalid type for ADST_STRING: %T (expected string)", value)
 		}
-		bufferSize := int(dataType.Size)
-		if bufferSize <= 0 {
-			bufferSize = 80 // Default ADS STRING length if not specified
+		data, err := writeString(val, dataType.Size, opts)
+		if err != nil {
+			return nil, err
 		}
-		data := adsprimitives.WriteString(val, bufferSize)
 		buf.Write(data)
 
 	case types.ADST_WSTRING:
@@ -243,29 +278,11 @@ func Serialize(value any, dataType types.AdsDataType, isArrayItem ...bool) ([]by
 		if !ok {
 			return nil, fmt.Errorf("invalid type for ADST_WSTRING: %T (expected string)", value)
 		}
-		bufferSize := int(dataType.Size)
-		if bufferSize <= 0 {
-			bufferSize = 160 // Default WSTRING size: 80 chars * 2 bytes (UTF-16LE)
-		}
-		wbuf := make([]byte, bufferSize)
-		// Proper UTF-16LE encoding
-		runes := []rune(val)
-		utf16Units := make([]uint16, len(runes))
-		for i, r := range runes {
-			utf16Units[i] = uint16(r)
-		}
-		// Write encoded runes as little-endian bytes
-		byteIdx := 0
-		maxChars := (bufferSize / 2) - 1 // Last two bytes are for null-terminator
-		for i := 0; i < len(utf16Units) && i < maxChars; i++ {
-			b0 := byte(utf16Units[i] & 0xFF)
-			b1 := byte(utf16Units[i] >> 8)
-			wbuf[byteIdx] = b0
-			wbuf[byteIdx+1] = b1
-			byteIdx += 2
+		data, err := writeWstring(val, dataType.Size, opts)
+		if err != nil {
+			return nil, err
 		}
-		// Null-terminated, wbuf is already zero-padded
-		buf.Write(wbuf)
+		buf.Write(data)
 
 	case types.ADST_BIGTYPE:
 		return nil, fmt.Errorf("ADST_BIGTYPE is not yet supported")
@@ -2,6 +2,7 @@ package adsserializer
 
 import (
 	"testing"
+	"time"
 
 	"github.com/jarmocluyse/ads-go/pkg/ads/types"
 	"github.com/stretchr/testify/assert"
@@ -643,3 +644,337 @@ func BenchmarkDeserialize_Struct(b *testing.B) {
 		_, _ = Deserialize(data, dataType)
 	}
 }
+
+// TestTimeTypes tests the conversion of IEC time types in both directions.
+func TestTimeTypes(t *testing.T) {
+	dt := time.Date(2024, 3, 15, 13, 45, 30, 0, time.UTC)
+	tests := []struct {
+		name     string
+		typeName string
+		dataType types.ADSDataType
+		data     []byte
+		value    any
+	}{
+		{"TIME", "TIME", types.ADST_UINT32, []byte{0x88, 0x13, 0x00, 0x00}, 5 * time.Second},
+		{"LTIME", "LTIME", types.ADST_UINT64, []byte{0x15, 0xCD, 0x5B, 0x07, 0x00, 0x00, 0x00, 0x00}, 123456789 * time.Nanosecond},
+		{"TOD", "TOD", types.ADST_UINT32, []byte{0x78, 0x0E, 0x95, 0x02}, 12*time.Hour + 2*time.Minute + 3*time.Second},
+		{"TIME_OF_DAY", "TIME_OF_DAY", types.ADST_UINT32, []byte{0xE8, 0x03, 0x00, 0x00}, time.Second},
+		{"LTOD", "LTOD", types.ADST_UINT64, []byte{0x00, 0xCA, 0x9A, 0x3B, 0x00, 0x00, 0x00, 0x00}, time.Second},
+		{"DATE", "DATE", types.ADST_UINT32, []byte{0x80, 0x8F, 0xF3, 0x65}, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"DT", "DT", types.ADST_UINT32, []byte{0xFA, 0x50, 0xF4, 0x65}, dt},
+		{"DATE_AND_TIME", "DATE_AND_TIME", types.ADST_UINT32, []byte{0xFA, 0x50, 0xF4, 0x65}, dt},
+		{"LDT", "LDT", types.ADST_UINT64, []byte{0x00, 0x44, 0x49, 0x1C, 0x15, 0xF4, 0xBC, 0x17}, dt},
+		{"lower case", "time", types.ADST_UINT32, []byte{0x64, 0x00, 0x00, 0x00}, 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dataType := types.AdsDataType{Type: tt.typeName, DataType: tt.dataType, Size: uint32(len(tt.data))}
+			value, err := Deserialize(tt.data, dataType)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			assert.Equal(t, tt.value, value)
+
+			data, err := Serialize(tt.value, dataType)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			assert.Equal(t, tt.data, data)
+		})
+	}
+}
+
+// TestTimeTypes_Serialize tests conversions and range checks when writing time types.
+func TestTimeTypes_Serialize(t *testing.T) {
+	timeType := types.AdsDataType{Type: "TIME", DataType: types.ADST_UINT32, Size: 4}
+
+	// Plain integers are still written as milliseconds
+	data, err := Serialize(uint32(250), timeType)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, []byte{0xFA, 0x00, 0x00, 0x00}, data)
+
+	// Sub-millisecond parts are truncated
+	data, err = Serialize(1500*time.Microsecond, timeType)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, []byte{0x01, 0x00, 0x00, 0x00}, data)
+
+	_, err = Serialize(-time.Second, timeType)
+	assert.Error(t, err, "negative duration")
+	_, err = Serialize(50*24*time.Hour, timeType)
+	assert.Error(t, err, "TIME holds about 49 days")
+	_, err = Serialize(24*time.Hour, types.AdsDataType{Type: "TOD", DataType: types.ADST_UINT32})
+	assert.Error(t, err, "not a time of day")
+	_, err = Serialize(time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC), types.AdsDataType{Type: "DT", DataType: types.ADST_UINT32})
+	assert.Error(t, err, "before the epoch")
+	ldt := types.AdsDataType{Type: "LDT", DataType: types.ADST_UINT64, Size: 8}
+	_, err = Serialize(time.Date(2262, 4, 12, 0, 0, 0, 0, time.UTC), ldt)
+	assert.ErrorContains(t, err, "is after 2262-04-11", "beyond the UnixNano range")
+	value, err := Deserialize([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, ldt)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, 2554, value.(time.Time).Year(), "reads do not wrap")
+
+	// DATE drops the time of day, other zones are converted to UTC
+	local := time.Date(2024, 3, 15, 23, 30, 0, 0, time.FixedZone("UTC-2", -2*3600))
+	data, err = Serialize(local, types.AdsDataType{Type: "DATE", DataType: types.ADST_UINT32})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, []byte{0x00, 0xE1, 0xF4, 0x65}, data, "2024-03-16 UTC")
+}
+
+// TestTimeTypes_Alias tests time conversion of alias types in Options.TimeAliases.
+func TestTimeTypes_Alias(t *testing.T) {
+	alias := types.AdsDataType{Type: "T_Timeout", DataType: types.ADST_UINT32, Size: 4}
+	data := []byte{0xE8, 0x03, 0x00, 0x00}
+
+	value, err := Deserialize(data, alias)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, uint32(1000), value, "unlisted alias is an integer")
+
+	opts := Options{TimeAliases: map[string]TimeType{"T_Timeout": TimeTypeTime}}
+	assert.Equal(t, TimeTypeTime, opts.LookupTimeType("t_timeout"))
+	assert.Equal(t, TimeTypeNone, Options{}.LookupTimeType("T_Timeout"))
+
+	value, err = DeserializeWithOptions(data, alias, opts)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, time.Second, value)
+
+	// Time fields in structs
+	structType := types.AdsDataType{
+		SubItems: []types.AdsDataType{
+			{Name: "Timeout", Type: "T_Timeout", DataType: types.ADST_UINT32, Size: 4},
+			{Name: "Count", DataType: types.ADST_UINT16, Offset: 4, Size: 2},
+		},
+	}
+	value, err = DeserializeWithOptions([]byte{0xE8, 0x03, 0x00, 0x00, 0x02, 0x00}, structType, opts)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, map[string]any{"Timeout": time.Second, "Count": uint16(2)}, value)
+
+	data, err = SerializeWithOptions(2*time.Second, alias, opts)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, []byte{0xD0, 0x07, 0x00, 0x00}, data)
+}
+
+// TestStrings_Wstring tests UTF-16LE WSTRING conversion.
+func TestStrings_Wstring(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		data  []byte
+	}{
+		{"German", "Größe", []byte{0x47, 0x00, 0x72, 0x00, 0xF6, 0x00, 0xDF, 0x00, 0x65, 0x00, 0x00, 0x00}},
+		{"Chinese", "你好", []byte{0x60, 0x4F, 0x7D, 0x59, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}},
+		{"Surrogate pair", "😀", []byte{0x3D, 0xD8, 0x00, 0xDE, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}},
+	}
+	dataType := types.AdsDataType{DataType: types.ADST_WSTRING, Size: 12} // WSTRING(5)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Serialize(tt.value, dataType)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			assert.Equal(t, tt.data, data)
+
+			value, err := Deserialize(tt.data, dataType)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			assert.Equal(t, tt.value, value)
+		})
+	}
+
+	// Reading stops at the declared size, even without a terminator
+	value, err := Deserialize([]byte{'A', 0x00, 'B', 0x00, 'C', 0x00}, types.AdsDataType{DataType: types.ADST_WSTRING, Size: 4})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, "AB", value)
+}
+
+// TestStrings_Windows1252 tests STRING conversion of older TwinCAT versions.
+func TestStrings_Windows1252(t *testing.T) {
+	dataType := types.AdsDataType{DataType: types.ADST_STRING, Size: 9}
+	opts := Options{StringEncoding: StringEncodingWindows1252}
+	raw := []byte{0x47, 0x72, 0xF6, 0xDF, 0x65, 0x20, 0x80, 0x00, 0x00}
+
+	data, err := SerializeWithOptions("Größe €", dataType, opts)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, raw, data)
+
+	value, err := DeserializeWithOptions(raw, dataType, opts)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, "Größe €", value)
+
+	// UTF-8 is the default
+	data, err = Serialize("Größe", dataType)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, []byte{0x47, 0x72, 0xC3, 0xB6, 0xC3, 0x9F, 0x65, 0x00, 0x00}, data)
+
+	_, err = SerializeWithOptions("你好", dataType, opts)
+	assert.ErrorContains(t, err, "cannot be encoded as Windows-1252")
+}
+
+// TestStrings_Overflow tests strings that exceed their declared size.
+func TestStrings_Overflow(t *testing.T) {
+	stringType := types.AdsDataType{DataType: types.ADST_STRING, Size: 6}   // STRING(5)
+	wstringType := types.AdsDataType{DataType: types.ADST_WSTRING, Size: 8} // WSTRING(3)
+	truncate := Options{TruncateStrings: true}
+
+	_, err := Serialize("Hello!", stringType)
+	assert.ErrorContains(t, err, "6 bytes do not fit STRING(5)")
+	_, err = Serialize("Hello", stringType)
+	assert.NoError(t, err, "exactly n characters fit")
+	_, err = Serialize("ABCD", wstringType)
+	assert.ErrorContains(t, err, "4 characters do not fit WSTRING(3)")
+
+	data, err := SerializeWithOptions("Hello!", stringType, truncate)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, []byte{'H', 'e', 'l', 'l', 'o', 0x00}, data)
+
+	// UTF-8 characters are not cut in half
+	data, err = SerializeWithOptions("Haßße", stringType, truncate)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, []byte{'H', 'a', 0xC3, 0x9F, 0x00, 0x00}, data)
+
+	// Surrogate pairs are not cut in half
+	data, err = SerializeWithOptions("AB😀", wstringType, truncate)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, []byte{'A', 0x00, 'B', 0x00, 0x00, 0x00, 0x00, 0x00}, data)
+}
+
+// enumDataType is E_State : (Idle := 0, Running := 3, Error := -1) OF INT.
+// EnumInfo holds the values unsigned, as the PLC reports them.
+var enumDataType = types.AdsDataType{
+	Type:     "E_State",
+	DataType: types.ADST_INT16,
+	Size:     2,
+	EnumInfo: []types.AdsEnumInfo{
+		{Name: "Idle", Value: 0},
+		{Name: "Running", Value: 3},
+		{Name: "Error", Value: 0xFFFF},
+	},
+}
+
+// TestEnums tests reading enum values in every mode.
+func TestEnums(t *testing.T) {
+	running := []byte{0x03, 0x00}
+
+	value, err := Deserialize(running, enumDataType)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, int16(3), value, "numbers by default")
+
+	value, err = DeserializeWithOptions(running, enumDataType, Options{EnumMode: EnumModeName})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, "E_State.Running", value)
+
+	value, err = DeserializeWithOptions([]byte{0xFF, 0xFF}, enumDataType, Options{EnumMode: EnumModeValue})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, EnumValue{Type: "E_State", Name: "Error", Value: -1}, value)
+	assert.Equal(t, "E_State.Error", value.(EnumValue).String())
+
+	// Undefined values are read as the integer
+	for _, mode := range []EnumMode{EnumModeName, EnumModeValue} {
+		value, err = DeserializeWithOptions([]byte{0x07, 0x00}, enumDataType, Options{EnumMode: mode})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		assert.Equal(t, int16(7), value, mode.String())
+	}
+
+	// Enum fields in structs and arrays
+	structType := types.AdsDataType{SubItems: []types.AdsDataType{enumDataType}}
+	structType.SubItems[0].Name = "State"
+	value, err = DeserializeWithOptions(running, structType, Options{EnumMode: EnumModeName})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, map[string]any{"State": "E_State.Running"}, value)
+	value, err = DeserializeWithOptions([]byte{0x07, 0x00}, structType, Options{EnumMode: EnumModeValue})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, map[string]any{"State": int16(7)}, value, "undefined value in a struct")
+
+	arrayType := enumDataType
+	arrayType.ArrayInfo = []types.AdsArrayInfo{{Length: 2}}
+	value, err = DeserializeWithOptions([]byte{0x03, 0x00, 0x00, 0x00}, arrayType, Options{EnumMode: EnumModeName})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, []any{"E_State.Running", "E_State.Idle"}, value)
+	value, err = DeserializeWithOptions([]byte{0x03, 0x00, 0x07, 0x00}, arrayType, Options{EnumMode: EnumModeName})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, []any{"E_State.Running", int16(7)}, value, "undefined value in an array")
+}
+
+// TestEnums_Serialize tests writing enum values by name, EnumValue and number.
+func TestEnums_Serialize(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		opts     Options
+		expected []byte
+		err      string
+	}{
+		{name: "Name", value: "Running", expected: []byte{0x03, 0x00}},
+		{name: "Qualified name", value: "E_State.Running", expected: []byte{0x03, 0x00}},
+		{name: "Case insensitive", value: "e_state.RUNNING", expected: []byte{0x03, 0x00}},
+		{name: "Negative value", value: "Error", expected: []byte{0xFF, 0xFF}},
+		{name: "EnumValue", value: EnumValue{Type: "E_State", Name: "Idle"}, expected: []byte{0x00, 0x00}},
+		{name: "EnumValue without name", value: EnumValue{Value: -1}, expected: []byte{0xFF, 0xFF}},
+		{name: "Number", value: int16(3), expected: []byte{0x03, 0x00}},
+		{name: "Undefined number by default", value: 7, expected: []byte{0x07, 0x00}},
+		{name: "Undefined number", value: 7, opts: Options{EnumMode: EnumModeName}, err: "7 is not defined"},
+		{name: "Undefined name", value: "Stopped", err: `"Stopped" is not defined`},
+		{name: "Other type name", value: "E_Mode.Running", err: `"E_Mode.Running" is not defined`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := SerializeWithOptions(tt.value, enumDataType, tt.opts)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			assert.Equal(t, tt.expected, data)
+		})
+	}
+}
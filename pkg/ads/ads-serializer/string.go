@@ -0,0 +1,125 @@
+package adsserializer
+
+import (
+	"encoding/binary"
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/jarmocluyse/ads-go/pkg/ads/utils"
+)
+
+// Default buffer sizes for strings without a declared size.
+const (
+	defaultStringSize  = 81  // STRING(80): 80 characters + null terminator
+	defaultWstringSize = 162 // WSTRING(80): 80 UTF-16 code units + null terminator
+)
+
+// StringEncoding is the encoding of STRING data.
+type StringEncoding int
+
+const (
+	StringEncodingUTF8        StringEncoding = iota // UTF-8 (runtimes with ADSUploadInfoFlagUtf8EncodedStringData)
+	StringEncodingWindows1252                       // Windows-1252 (older TwinCAT versions)
+)
+
+// String returns the name of the encoding.
+func (e StringEncoding) String() string {
+	switch e {
+	case StringEncodingWindows1252:
+		return "Windows-1252"
+	default:
+		return "UTF-8"
+	}
+}
+
+// readString reads a null-terminated STRING of at most size bytes.
+func readString(data []byte, size uint32, encoding StringEncoding) string {
+	data = limitString(data, size)
+	for i, b := range data {
+		if b == 0 {
+			data = data[:i]
+			break
+		}
+	}
+	if encoding == StringEncodingWindows1252 {
+		return utils.DecodeWindows1252(data)
+	}
+	return string(data)
+}
+
+// writeString encodes a STRING into a zero padded buffer of size bytes. One
+// byte is kept for the null terminator.
+func writeString(value string, size uint32, opts Options) ([]byte, error) {
+	if size == 0 {
+		size = defaultStringSize
+	}
+	var encoded []byte
+	if opts.StringEncoding == StringEncodingWindows1252 {
+		var err error
+		if encoded, err = utils.EncodeWindows1252(value); err != nil {
+			return nil, fmt.Errorf("invalid value for ADST_STRING: %w", err)
+		}
+	} else {
+		encoded = []byte(value)
+	}
+
+	capacity := int(size) - 1
+	if len(encoded) > capacity {
+		if !opts.TruncateStrings {
+			return nil, fmt.Errorf("invalid value for ADST_STRING: %d bytes do not fit STRING(%d)", len(encoded), capacity)
+		}
+		encoded = encoded[:capacity]
+		if opts.StringEncoding == StringEncodingUTF8 {
+			// Do not leave half a character behind
+			for len(encoded) > 0 && !utf8.Valid(encoded) {
+				encoded = encoded[:len(encoded)-1]
+			}
+		}
+	}
+
+	buf := make([]byte, size)
+	copy(buf, encoded)
+	return buf, nil
+}
+
+// readWstring reads a null-terminated UTF-16LE WSTRING of at most size bytes.
+func readWstring(data []byte, size uint32) string {
+	data = limitString(data, size)
+	return utils.DecodePlcWstringBuffer(data[:len(data)&^1])
+}
+
+// writeWstring encodes a WSTRING as UTF-16LE into a zero padded buffer of size
+// bytes. One code unit is kept for the null terminator.
+func writeWstring(value string, size uint32, opts Options) ([]byte, error) {
+	if size == 0 {
+		size = defaultWstringSize
+	}
+	units := utf16.Encode([]rune(value))
+
+	capacity := int(size)/2 - 1
+	if len(units) > capacity {
+		if !opts.TruncateStrings {
+			return nil, fmt.Errorf("invalid value for ADST_WSTRING: %d characters do not fit WSTRING(%d)", len(units), capacity)
+		}
+		units = units[:capacity]
+		// Do not leave half a surrogate pair behind
+		if capacity > 0 && utf16.IsSurrogate(rune(units[capacity-1])) && units[capacity-1] < 0xDC00 {
+			units = units[:capacity-1]
+		}
+	}
+
+	buf := make([]byte, size)
+	for i, unit := range units {
+		binary.LittleEndian.PutUint16(buf[2*i:], unit)
+	}
+	return buf, nil
+}
+
+// limitString cuts data to the declared size of a string, if it has one.
+func limitString(data []byte, size uint32) []byte {
+	if size > 0 && int(size) < len(data) {
+		return data[:size]
+	}
+	return data
+}
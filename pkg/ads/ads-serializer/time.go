@@ -0,0 +1,184 @@
+package adsserializer
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	adsprimitives "github.com/jarmocluyse/ads-go/pkg/ads/ads-primitives"
+)
+
+// TimeType identifies an IEC 61131-3 time type.
+type TimeType int
+
+const (
+	TimeTypeNone         TimeType = iota // not a time type
+	TimeTypeTime                         // TIME: milliseconds (uint32) as time.Duration
+	TimeTypeLTime                        // LTIME: nanoseconds (uint64) as time.Duration
+	TimeTypeDate                         // DATE: seconds since 1970-01-01 (uint32) as time.Time
+	TimeTypeLDate                        // LDATE: nanoseconds since 1970-01-01 (uint64) as time.Time
+	TimeTypeDateAndTime                  // DT: seconds since 1970-01-01 (uint32) as time.Time
+	TimeTypeLDateAndTime                 // LDT: nanoseconds since 1970-01-01 (uint64) as time.Time
+	TimeTypeTimeOfDay                    // TOD: milliseconds since midnight (uint32) as time.Duration
+	TimeTypeLTimeOfDay                   // LTOD: nanoseconds since midnight (uint64) as time.Duration
+)
+
+// String returns the IEC name of the time type.
+func (t TimeType) String() string {
+	switch t {
+	case TimeTypeTime:
+		return "TIME"
+	case TimeTypeLTime:
+		return "LTIME"
+	case TimeTypeDate:
+		return "DATE"
+	case TimeTypeLDate:
+		return "LDATE"
+	case TimeTypeDateAndTime:
+		return "DT"
+	case TimeTypeLDateAndTime:
+		return "LDT"
+	case TimeTypeTimeOfDay:
+		return "TOD"
+	case TimeTypeLTimeOfDay:
+		return "LTOD"
+	default:
+		return "None"
+	}
+}
+
+// isLong reports whether the time type is stored in 64 bits.
+func (t TimeType) isLong() bool {
+	return t == TimeTypeLTime || t == TimeTypeLDate || t == TimeTypeLDateAndTime || t == TimeTypeLTimeOfDay
+}
+
+// resolution returns the unit of the stored integer.
+func (t TimeType) resolution() time.Duration {
+	switch t {
+	case TimeTypeTime, TimeTypeTimeOfDay:
+		return time.Millisecond
+	case TimeTypeDate, TimeTypeDateAndTime:
+		return time.Second
+	default:
+		return time.Nanosecond
+	}
+}
+
+// maxUnixNanoTime is the last time whose UnixNano fits into an int64.
+var maxUnixNanoTime = time.Unix(0, math.MaxInt64).UTC()
+
+// timeTypeNames maps the IEC type names (and their long forms) to time types.
+var timeTypeNames = map[string]TimeType{
+	"TIME":           TimeTypeTime,
+	"LTIME":          TimeTypeLTime,
+	"DATE":           TimeTypeDate,
+	"LDATE":          TimeTypeLDate,
+	"DT":             TimeTypeDateAndTime,
+	"DATE_AND_TIME":  TimeTypeDateAndTime,
+	"LDT":            TimeTypeLDateAndTime,
+	"LDATE_AND_TIME": TimeTypeLDateAndTime,
+	"TOD":            TimeTypeTimeOfDay,
+	"TIME_OF_DAY":    TimeTypeTimeOfDay,
+	"LTOD":           TimeTypeLTimeOfDay,
+	"LTIME_OF_DAY":   TimeTypeLTimeOfDay,
+}
+
+// LookupTimeType returns the time type of a type name, or TimeTypeNone if it
+// is neither an IEC time type nor one of TimeAliases. Names are not case
+// sensitive.
+func (o Options) LookupTimeType(name string) TimeType {
+	if timeType, ok := timeTypeNames[strings.ToUpper(name)]; ok {
+		return timeType
+	}
+	if timeType, ok := o.TimeAliases[name]; ok {
+		return timeType
+	}
+	for alias, timeType := range o.TimeAliases {
+		if strings.EqualFold(alias, name) {
+			return timeType
+		}
+	}
+	return TimeTypeNone
+}
+
+// readTime reads a value of a time type: time.Duration for TIME, LTIME, TOD
+// and LTOD, time.Time (UTC) for DATE, LDATE, DT and LDT.
+func readTime(data []byte, timeType TimeType) (any, error) {
+	var raw uint64
+	if timeType.isLong() {
+		value, err := adsprimitives.ReadUint64(data)
+		if err != nil {
+			return nil, err
+		}
+		raw = value
+	} else {
+		value, err := adsprimitives.ReadUint32(data)
+		if err != nil {
+			return nil, err
+		}
+		raw = uint64(value)
+	}
+
+	switch timeType {
+	case TimeTypeDate, TimeTypeLDate, TimeTypeDateAndTime, TimeTypeLDateAndTime:
+		if timeType.isLong() {
+			// Split so values beyond the int64 nanosecond range do not wrap
+			return time.Unix(int64(raw/uint64(time.Second)), int64(raw%uint64(time.Second))).UTC(), nil
+		}
+		return time.Unix(int64(raw), 0).UTC(), nil
+	default:
+		return time.Duration(raw) * timeType.resolution(), nil
+	}
+}
+
+// writeTime converts a time.Duration (TIME, LTIME, TOD, LTOD) or time.Time
+// (DATE, LDATE, DT, LDT) to its binary form. ok is false if value has another
+// Go type, so the caller can fall back to the integer representation.
+func writeTime(value any, timeType TimeType) ([]byte, bool, error) {
+	var raw int64
+	switch timeType {
+	case TimeTypeDate, TimeTypeLDate, TimeTypeDateAndTime, TimeTypeLDateAndTime:
+		t, ok := value.(time.Time)
+		if !ok {
+			return nil, false, nil
+		}
+		t = t.UTC()
+		if timeType == TimeTypeDate || timeType == TimeTypeLDate {
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		}
+		if t.Before(time.Unix(0, 0)) {
+			return nil, true, fmt.Errorf("invalid value for %s: %s is before 1970-01-01", timeType, t)
+		}
+		if timeType.isLong() {
+			if t.After(maxUnixNanoTime) {
+				return nil, true, fmt.Errorf("invalid value for %s: %s is after %s", timeType, t, maxUnixNanoTime)
+			}
+			raw = t.UnixNano()
+		} else {
+			raw = t.Unix()
+		}
+	default:
+		d, ok := value.(time.Duration)
+		if !ok {
+			return nil, false, nil
+		}
+		if d < 0 {
+			return nil, true, fmt.Errorf("invalid value for %s: negative duration %s", timeType, d)
+		}
+		if (timeType == TimeTypeTimeOfDay || timeType == TimeTypeLTimeOfDay) && d >= 24*time.Hour {
+			return nil, true, fmt.Errorf("invalid value for %s: %s is not a time of day", timeType, d)
+		}
+		raw = int64(d / timeType.resolution())
+	}
+
+	if timeType.isLong() {
+		data, err := adsprimitives.WriteUint64(uint64(raw))
+		return data, true, err
+	}
+	if raw > 0xFFFFFFFF {
+		return nil, true, fmt.Errorf("invalid value for %s: %v is out of range", timeType, value)
+	}
+	data, err := adsprimitives.WriteUint32(uint32(raw))
+	return data, true, err
+}
@@ -318,6 +318,21 @@ func TestParseExtendedSystemState(t *testing.T) {
 	}
 }
 
+func TestExtendedSystemStateRedundancyFlags(t *testing.T) {
+	data := buildExtendedSystemStateData(types.ADSStateRun, 0, 1, 3, 1, 4026, 2, 4,
+		uint16(types.ADSSystemServiceStateFlagRedundancySystem|types.ADSSystemServiceStateFlagRedundancyActive))
+	state, err := ParseExtendedSystemState(data)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, types.ADSSystemServiceStateFlagRedundancySystem|types.ADSSystemServiceStateFlagRedundancyActive, state.SystemFlags())
+	assert.True(t, state.IsRedundancySystem())
+	assert.True(t, state.IsRedundancyActive())
+	assert.False(t, state.IsRedundancyPrimary())
+
+	assert.False(t, ExtendedSystemState{}.IsRedundancySystem())
+}
+
 func TestCheckExtendedSystemState(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -53,10 +53,33 @@ type ExtendedSystemState struct {
 	// OsType is the operating system type ID
 	OsType uint8
 
-	// Flags contains system service state flags (interpretation is system-specific)
+	// Flags contains the system service state flags, see SystemFlags
 	Flags uint16
 }
 
+// SystemFlags returns Flags as system service state flags.
+func (s ExtendedSystemState) SystemFlags() types.ADSSystemServiceStateFlags {
+	return types.ADSSystemServiceStateFlags(s.Flags)
+}
+
+// IsRedundancySystem reports whether the system is part of a controller
+// redundancy pair.
+func (s ExtendedSystemState) IsRedundancySystem() bool {
+	return s.SystemFlags()&types.ADSSystemServiceStateFlagRedundancySystem != 0
+}
+
+// IsRedundancyPrimary reports whether the system is the configured primary
+// controller of its redundancy pair.
+func (s ExtendedSystemState) IsRedundancyPrimary() bool {
+	return s.SystemFlags()&types.ADSSystemServiceStateFlagRedundancyPrimary != 0
+}
+
+// IsRedundancyActive reports whether the system currently controls the
+// machine. Only one controller of a redundancy pair is active.
+func (s ExtendedSystemState) IsRedundancyActive() bool {
+	return s.SystemFlags()&types.ADSSystemServiceStateFlagRedundancyActive != 0
+}
+
 // DeviceInfo represents the response for an ADS ReadDeviceInfo command.
 //
 // Device information includes version details and the device name,
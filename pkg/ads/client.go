@@ -7,9 +7,12 @@ import (
 	"log/slog"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	adsserializer "github.com/jarmocluyse/ads-go/pkg/ads/ads-serializer"
 	"github.com/jarmocluyse/ads-go/pkg/ads/ads-stateinfo"
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
 )
 
 // Response represents a response from an ADS device.
@@ -18,27 +21,61 @@ type Response struct {
 	Error error  // received ads error
 }
 
+// pendingRequest tracks a request that is waiting for its response.
+type pendingRequest struct {
+	ch     chan Response // channel the response is written to
+	sentAt time.Time     // time the invoke id was assigned
+}
+
 // Client represents an ADS client.
 type Client struct {
-	conn                    net.Conn                       // tcp connection
-	settings                ClientSettings                 // client settings
-	mutex                   sync.Mutex                     // mutex for invoke id and request map
-	invokeID                uint32                         // last used invoke id
-	requests                map[uint32]chan Response       // channel map to write the responses to
-	localAmsAddr            AmsAddress                     // local asigned ams adres
-	receiveBuffer           bytes.Buffer                   // Buffer for incoming data
-	logger                  *slog.Logger                   // logger
-	subscriptions           map[uint32]*ActiveSubscription // active subscriptions map[notificationHandle]subscription
-	subscriptionsMutex      sync.RWMutex                   // mutex for subscriptions map
-	currentState            *adsstateinfo.SystemState      // current cached TwinCAT system state
-	stateMutex              sync.RWMutex                   // protects currentState
-	statePollerTimer        *time.Timer                    // state polling timer
-	statePollerID           int                            // unique poller ID to prevent multiple timers
-	statePollerMutex        sync.Mutex                     // protects timer operations
-	extendedStateSupported  *bool                          // nil = unknown, true/false = tested
-	lastRestartIndex        *uint16                        // last seen restart index (nil if not yet read or not supported)
-	extendedStateMutex      sync.RWMutex                   // protects extended state fields
-	consecutiveReadFailures int                            // number of consecutive state read failures
+	conn                    net.Conn                             // tcp connection (protected by connMutex)
+	connState               ConnectionState                      // connection lifecycle state (protected by connMutex)
+	wasConnected            bool                                 // a connection was established before (protected by connMutex)
+	connMutex               sync.RWMutex                         // protects conn, connState, wasConnected, localAmsAddr, closing and callbacksStopped
+	closing                 bool                                 // Close was called, new requests are rejected (protected by connMutex)
+	targetShutdown          atomic.Bool                          // RebootTarget/ShutdownTarget in progress, the connection loss is expected
+	callbacksStopped        bool                                 // Close stopped dispatching notification callbacks (protected by connMutex)
+	inFlight                sync.WaitGroup                       // send calls in progress
+	callbacks               sync.WaitGroup                       // notification callbacks in progress
+	statusEvents            []statusEvent                        // queued OnStatusChange invocations
+	statusDispatching       bool                                 // a goroutine is delivering statusEvents
+	statusEventsMutex       sync.Mutex                           // protects statusEvents and statusDispatching
+	settings                ClientSettings                       // client settings
+	mutex                   sync.Mutex                           // mutex for invoke id and request map
+	invokeID                uint32                               // last used invoke id
+	requests                map[uint32]*pendingRequest           // pending requests keyed by invoke id
+	localAmsAddr            AmsAddress                           // local asigned ams adres
+	receiveBuffer           bytes.Buffer                         // Buffer for incoming data (protected by receiveMutex)
+	receiveMutex            sync.Mutex                           // serializes buffer writes of the receive goroutine with Connect
+	receiveBufferLen        atomic.Int64                         // bytes left in receiveBuffer after the last read (for Debug)
+	receiveBufferCap        atomic.Int64                         // capacity of receiveBuffer after the last read (for Debug)
+	logger                  *slog.Logger                         // logger
+	subscriptions           map[uint32]*ActiveSubscription       // active subscriptions map[notificationHandle]subscription
+	clientSubscriptions     map[uint32]*ActiveSubscription       // client-side emulated subscriptions map[localHandle]subscription
+	lastClientHandle        uint32                               // last local handle assigned to a client-side subscription
+	subscriptionsMutex      sync.RWMutex                         // mutex for subscriptions, clientSubscriptions and lastClientHandle
+	multiplexGroups         map[string]*multiplexGroup           // shared parent notifications of multiplexed subscriptions
+	multiplexMutex          sync.Mutex                           // protects multiplexGroups, held while a group is created or removed
+	pollGroups              map[string]*pollGroup                // poll groups of polled subscriptions by port and cycle time
+	pollMutex               sync.Mutex                           // protects pollGroups
+	unknownHandles          map[uint16]int                       // consecutive samples with unknown handles per port (protected by receiveMutex)
+	symbolVersions          map[uint16]uint8                     // last seen symbol version per port (protected by stateMutex)
+	currentState            *adsstateinfo.SystemState            // current cached TwinCAT system state
+	stateMutex              sync.RWMutex                         // protects currentState and symbolVersions
+	statePollerTimer        *time.Timer                          // state polling timer
+	statePollerID           int                                  // unique poller ID to prevent multiple timers
+	statePollerMutex        sync.Mutex                           // protects timer operations and stateSubscription
+	stateSubscription       *ActiveSubscription                  // device state notification (StateNotifications)
+	plcStateSubscriptions   map[uint16]*ActiveSubscription       // device state notifications of runtime ports (protected by statePollerMutex)
+	plcStates               map[uint16]*adsstateinfo.SystemState // cached state per tracked runtime port (nil = unknown)
+	plcStateMutex           sync.Mutex                           // protects plcStates
+	extendedStateSupported  *bool                                // nil = unknown, true/false = tested
+	lastRestartIndex        *uint16                              // last seen restart index (nil if not yet read or not supported)
+	extendedStateMutex      sync.RWMutex                         // protects extended state fields
+	consecutiveReadFailures int                                  // number of consecutive state read failures (protected by stateMutex)
+	targetInfo              *TargetInfo                          // result of the last completed ProbeTarget (nil = not probed)
+	targetInfoMutex         sync.RWMutex                         // protects targetInfo
 
 	// onConnCaptured is an optional test hook called from receive() immediately
 	// after it captures c.conn into a local variable. Tests use this to
@@ -68,6 +105,11 @@ type ClientSettings struct {
 	// Use this hook for cleanup or logging.
 	OnDisconnect func(client *Client)
 
+	// OnStatusChange is called when the connection lifecycle state changes (asynchronous).
+	// The hook receives the client, the new state and the previous state.
+	// Calls are delivered in order on a single goroutine, so a slow hook delays later events.
+	OnStatusChange func(client *Client, newState ConnectionState, oldState ConnectionState)
+
 	// OnConnectionLost is called when connection drops unexpectedly (asynchronous).
 	// The hook receives the client and the error that caused the disconnection.
 	// Use this hook for error handling, reconnection logic, or alerting.
@@ -89,6 +131,51 @@ type ClientSettings struct {
 	// glitch without declaring the connection lost.
 	// Set to 1 to trigger OnConnectionLost on the first failure.
 	MaxConsecutiveReadFailures int
+
+	// StateNotifications subscribes to the device state of the system service
+	// instead of relying on polling alone (default: false). State changes (e.g.
+	// Run→Stop) reach OnStateChange within StateNotificationCycleTime. The
+	// poller keeps running with StatePollingInterval as a heartbeat that detects
+	// unreachable targets and restarts; a longer interval is fine.
+	// If the target rejects the notification, the client falls back to polling.
+	StateNotifications bool
+
+	// StateNotificationCycleTime is how often the target checks its state for
+	// StateNotifications (default: 10ms).
+	StateNotificationCycleTime time.Duration
+
+	// OnPlcStateChange is called when the state of a PLC runtime port changes (asynchronous).
+	// The hook receives the client, the port (e.g. 852), the new state and the previous
+	// state (nil on the first read). Ports are tracked once they are used, see GetPlcState.
+	OnPlcStateChange func(client *Client, port uint16, newState *adsstateinfo.SystemState, oldState *adsstateinfo.SystemState)
+
+	// PlcStateGate decides whether ReadValue and WriteValue are allowed depending on the
+	// state of the PLC runtime on the port (default: PlcStateGateKnown).
+	PlcStateGate PlcStateGate
+
+	// PlcControlTimeout is how long StartPlc, StopPlc, ResetPlcCold and ResetPlcOrigin
+	// wait for the runtime to reach the requested state (10s assumed if empty).
+	PlcControlTimeout time.Duration
+
+	// TargetInfoPort is the PLC runtime port whose symbol upload flags and
+	// services are probed for TargetInfo (851 assumed if empty).
+	TargetInfoPort uint16
+
+	// TruncateStrings makes WriteValue cut STRING and WSTRING values that exceed
+	// the declared size instead of failing.
+	TruncateStrings bool
+
+	// EnumMode selects how ReadValue and subscriptions return enum values: the
+	// integer (default), the name ("E_State.Running") or an adsserializer.EnumValue.
+	// Values the enum does not define are returned as the integer in every
+	// mode. WriteValue accepts all three; other modes than EnumModeNumber reject
+	// integers the enum does not define.
+	EnumMode adsserializer.EnumMode
+
+	// TimeAliases maps alias type names of IEC time types (e.g. T_Timeout
+	// declared as "TYPE T_Timeout : TIME; END_TYPE") to their time type, so
+	// ReadValue, WriteValue and subscriptions convert them like the base type.
+	TimeAliases map[string]adsserializer.TimeType
 }
 
 // LoadDefaults sets the default values for any unset ClientSettings fields.
@@ -108,6 +195,15 @@ func (cs *ClientSettings) LoadDefaults() {
 	if cs.StatePollingInterval == 0 {
 		cs.StatePollingInterval = 2 * time.Second
 	}
+	if cs.StateNotificationCycleTime == 0 {
+		cs.StateNotificationCycleTime = 10 * time.Millisecond
+	}
+	if cs.PlcControlTimeout == 0 {
+		cs.PlcControlTimeout = 10 * time.Second
+	}
+	if cs.TargetInfoPort == 0 {
+		cs.TargetInfoPort = types.ADSReservedPortTc3Plc1
+	}
 	if cs.MaxConsecutiveReadFailures == 0 {
 		cs.MaxConsecutiveReadFailures = 1
 	}
@@ -122,10 +218,17 @@ func NewClient(settings ClientSettings, logger *slog.Logger) *Client {
 	logger.Info("NewClient: Initializing new ADS client.")
 	settings.LoadDefaults()
 	client := &Client{
-		settings:      settings,
-		requests:      make(map[uint32]chan Response),
-		subscriptions: make(map[uint32]*ActiveSubscription),
-		logger:        logger,
+		settings:              settings,
+		requests:              make(map[uint32]*pendingRequest),
+		subscriptions:         make(map[uint32]*ActiveSubscription),
+		clientSubscriptions:   make(map[uint32]*ActiveSubscription),
+		multiplexGroups:       make(map[string]*multiplexGroup),
+		pollGroups:            make(map[string]*pollGroup),
+		unknownHandles:        make(map[uint16]int),
+		symbolVersions:        make(map[uint16]uint8),
+		plcStates:             make(map[uint16]*adsstateinfo.SystemState),
+		plcStateSubscriptions: make(map[uint16]*ActiveSubscription),
+		logger:                logger,
 	}
 	logger.Info("NewClient: ADS client initialized.")
 	return client
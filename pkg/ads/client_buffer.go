@@ -1,7 +1,6 @@
 package ads
 
 import (
-	"fmt"
 	"io"
 
 	adserrors "github.com/jarmocluyse/ads-go/pkg/ads/ads-errors"
@@ -14,7 +13,12 @@ import (
 // does not affect this goroutine, and so the deferred Close() only closes the
 // connection this goroutine was started for.
 func (c *Client) receive() {
+	c.connMutex.RLock()
 	conn := c.conn // capture at goroutine start
+	c.connMutex.RUnlock()
+	if conn == nil {
+		return
+	}
 	// Signal test hook that conn has been captured (eliminates sleep-based sync).
 	if c.onConnCaptured != nil {
 		c.onConnCaptured()
@@ -42,18 +46,18 @@ func (c *Client) receive() {
 			// Only invoke OnConnectionLost if this goroutine still owns the active conn.
 			// If Connect() has already replaced c.conn, a new receive() is running and
 			// we must not fire the hook again (which would kick off another reconnect loop).
-			if c.conn == conn {
-				c.invokeConnectionLostHook(err)
-			} else {
-				c.logger.Info("receive: Stale goroutine exiting — connection already replaced, skipping hook.")
-			}
+			// Disconnect() releases ownership before closing, so a graceful close is skipped too.
+			c.handleConnectionLost(conn, err)
 
 			return // Exit goroutine on error or EOF
 		}
 
 		// Guard: only write if this goroutine still owns the active connection.
 		// A stale goroutine must not corrupt the new connection's receive buffer.
-		if c.conn != conn {
+		// The check happens under receiveMutex, which Connect() holds while swapping conn.
+		c.receiveMutex.Lock()
+		if !c.ownsConn(conn) {
+			c.receiveMutex.Unlock()
 			c.logger.Info("receive: Stale goroutine detected after read — discarding data and exiting.")
 			return
 		}
@@ -63,6 +67,9 @@ func (c *Client) receive() {
 
 		// Process packets from the receive buffer
 		c.processReceiveBuffer()
+		c.receiveBufferLen.Store(int64(c.receiveBuffer.Len()))
+		c.receiveBufferCap.Store(int64(c.receiveBuffer.Cap()))
+		c.receiveMutex.Unlock()
 	}
 }
 
@@ -86,22 +93,23 @@ func (c *Client) processReceiveBuffer() {
 		// Check if this is a notification packet (command 8)
 		if types.ADSCommand(packet.AdsCommand) == types.ADSCommandNotification {
 			c.logger.Debug("receive: Received notification packet, routing to handleNotification")
-			c.handleNotification(packet.Data)
+			c.handleNotification(packet.SourceAmsAddress.Port, packet.Data)
 			continue // Don't look for request channel, notifications don't have invokeIDs
 		}
 
 		c.mutex.Lock()
-		ch, ok := c.requests[packet.InvokeId]
+		req, ok := c.requests[packet.InvokeId]
 		c.mutex.Unlock()
 
 		if ok {
+			ch := req.ch
 			c.logger.Debug("receive: Found channel for InvokeID, sending response.", "invokeID", packet.InvokeId)
 			if packet.ErrorCode != 0 {
 				errorString := adserrors.ErrorCodeToString(packet.ErrorCode)
 				c.logger.Error("receive: ADS error received", "invokeID", packet.InvokeId, "errorCode", packet.ErrorCode, "errorDesc", errorString)
-				ch <- Response{Error: fmt.Errorf("ADS error: %s", errorString)}
+				deliverResponse(ch, Response{Error: &adserrors.AdsError{Code: packet.ErrorCode}})
 			} else {
-				ch <- Response{Data: packet.Data}
+				deliverResponse(ch, Response{Data: packet.Data})
 			}
 		} else {
 			c.logger.Warn("receive: No channel found for InvokeID, discarding packet.", "invokeID", packet.InvokeId)
@@ -109,6 +117,16 @@ func (c *Client) processReceiveBuffer() {
 	}
 }
 
+// deliverResponse hands a response to a waiting request without blocking.
+// The channel is buffered; if it is already full the request was completed
+// (for example failed by a connection loss) and the response is dropped.
+func deliverResponse(ch chan Response, resp Response) {
+	select {
+	case ch <- resp:
+	default:
+	}
+}
+
 // Read packet length from AMS/TCP header (bytes 2-5)
 // We need to peek without advancing the buffer's read pointer
 // to check if we received the full packet
@@ -0,0 +1,166 @@
+package ads
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Close shuts the client down gracefully. The client cannot be reused afterwards.
+//
+// Close runs the following steps in order:
+//  1. New requests are rejected with ErrClientClosed.
+//  2. Requests already in flight are allowed to finish.
+//  3. All notifications are deleted on the target.
+//  4. Notification callbacks that are still running are allowed to return.
+//  5. The ADS port is released and the connection is closed.
+//
+// If ctx expires while waiting in step 2 or 4, Close stops waiting and
+// continues with the teardown. In that case ctx.Err() is returned (wrapped).
+// Notifications are only deleted on the target while ctx is not done.
+// Calling Close on a closed or closing client returns nil.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	if err := client.Close(ctx); err != nil {
+//	    log.Printf("close did not drain cleanly: %v", err)
+//	}
+func (c *Client) Close(ctx context.Context) error {
+	c.logger.Debug("Close: Closing client.")
+
+	c.connMutex.Lock()
+	if c.closing {
+		c.connMutex.Unlock()
+		c.logger.Debug("Close: Client already closed or closing.")
+		return nil
+	}
+	c.closing = true
+	c.connMutex.Unlock()
+
+	var ctxErr error
+
+	// Wait for in-flight requests
+	if err := waitContext(ctx, &c.inFlight); err != nil {
+		c.logger.Warn("Close: Stopped waiting for in-flight requests", "error", err)
+		ctxErr = err
+	}
+
+	// Stop state monitoring
+	c.stopStatePoller()
+	c.stopStateNotification(c.transmit)
+
+	// End channel subscriptions with ErrClientClosed rather than ErrSubscriptionClosed
+	for _, sub := range c.subscriptionList() {
+		sub.terminate(ErrClientClosed)
+	}
+
+	// Delete notifications while the connection is still up
+	conn, _, connErr := c.activeConn()
+	if connErr == nil && ctx.Err() == nil {
+		if err := c.unsubscribeAll(c.transmit); err != nil {
+			c.logger.Warn("Close: Error unsubscribing from all subscriptions", "error", err)
+		}
+	}
+	for _, sub := range c.subscriptionList() {
+		c.removeSubscription(sub)
+		sub.close(ErrClientClosed)
+	}
+	c.multiplexMutex.Lock()
+	clear(c.multiplexGroups)
+	c.multiplexMutex.Unlock()
+
+	// Stop dispatching callbacks and wait for the running ones
+	c.connMutex.Lock()
+	c.callbacksStopped = true
+	c.connMutex.Unlock()
+	if err := waitContext(ctx, &c.callbacks); err != nil {
+		c.logger.Warn("Close: Stopped waiting for notification callbacks", "error", err)
+		if ctxErr == nil {
+			ctxErr = err
+		}
+	}
+
+	// Tear down the connection
+	if connErr == nil {
+		if err := c.unregisterAdsPort(conn); err != nil {
+			c.logger.Error("Close: Error unregistering ADS port", "error", err)
+		}
+	}
+
+	c.connMutex.Lock()
+	conn = c.conn
+	c.conn = nil
+	c.wasConnected = false
+	c.setConnectionStateLocked(ConnectionStateClosed)
+	c.connMutex.Unlock()
+
+	if conn != nil {
+		if closeErr := conn.Close(); closeErr != nil {
+			c.logger.Debug("Close: Failed to close connection", "error", closeErr)
+		}
+	}
+	c.failPendingRequests(ErrClientClosed)
+
+	c.stateMutex.Lock()
+	c.currentState = nil
+	c.consecutiveReadFailures = 0
+	c.stateMutex.Unlock()
+	c.clearPlcStates()
+
+	if connErr == nil {
+		// Invoke OnDisconnect hook asynchronously (fire-and-forget)
+		go c.invokeHook("OnDisconnect", func() {
+			c.settings.OnDisconnect(c)
+		})
+	}
+
+	c.logger.Info("Close: Client closed.")
+	if ctxErr != nil {
+		return fmt.Errorf("Close: %w", ctxErr)
+	}
+	return nil
+}
+
+// beginRequest registers an in-flight request.
+// Returns ErrClientClosed once Close has been called.
+// The caller must call c.inFlight.Done when the request is finished.
+func (c *Client) beginRequest() error {
+	c.connMutex.RLock()
+	defer c.connMutex.RUnlock()
+	if c.closing {
+		return ErrClientClosed
+	}
+	// Added under the lock so Close cannot start waiting in between.
+	c.inFlight.Add(1)
+	return nil
+}
+
+// beginCallback registers a notification callback.
+// Returns false once Close has stopped callback dispatching.
+// The caller must call c.callbacks.Done when the callback returned.
+func (c *Client) beginCallback() bool {
+	c.connMutex.RLock()
+	defer c.connMutex.RUnlock()
+	if c.callbacksStopped {
+		return false
+	}
+	c.callbacks.Add(1)
+	return true
+}
+
+// waitContext waits for wg or until ctx is done.
+func waitContext(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
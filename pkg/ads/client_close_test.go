@@ -0,0 +1,141 @@
+package ads
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// closeTestHandler answers AddNotification with handle 1, counts deleted
+// notifications and delays Read requests by readDelay.
+// A negative readDelay drops Read requests.
+func closeTestHandler(readDelay time.Duration, deletes *atomic.Int32) fakeRouterHandler {
+	return func(cmd types.ADSCommand, port uint16, data []byte) []byte {
+		switch cmd {
+		case types.ADSCommandAddNotification:
+			resp := make([]byte, 8)
+			binary.LittleEndian.PutUint32(resp[4:8], 1)
+			return resp
+		case types.ADSCommandDeleteNotification:
+			deletes.Add(1)
+		case types.ADSCommandReadWrite:
+			if indexGroup, count := sumCommandHeader(data); indexGroup == types.ADSReservedIndexGroupSumCommandDelDevNote {
+				deletes.Add(int32(count))
+			}
+		case types.ADSCommandRead:
+			if readDelay < 0 {
+				return nil
+			}
+			time.Sleep(readDelay)
+		}
+		return defaultFakeHandler(cmd, port, data)
+	}
+}
+
+// TestCloseDrainsInFlightRequests verifies that Close waits for running
+// requests, deletes notifications and rejects new requests.
+func TestCloseDrainsInFlightRequests(t *testing.T) {
+	var deletes atomic.Int32
+	router := newFakeRouter(t, closeTestHandler(100*time.Millisecond, &deletes))
+	c := NewClient(router.settings(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := c.SubscribeRaw(851, 0x4020, 0, 4, func(SubscriptionData) {}, SubscriptionSettings{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	readErr := make(chan error, 1)
+	go func() {
+		_, err := c.ReadRaw(851, 0x4020, 0, 4)
+		readErr <- err
+	}()
+	waitFor(t, time.Second, func() bool { return len(c.Debug().PendingRequests) == 1 }, "pending read")
+
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.NoError(t, <-readErr)
+	assert.Equal(t, int32(1), deletes.Load())
+	assert.Equal(t, ConnectionStateClosed, c.ConnectionState())
+	assert.Empty(t, c.Debug().Subscriptions)
+
+	_, err := c.ReadRaw(851, 0x4020, 0, 4)
+	assert.True(t, errors.Is(err, ErrClientClosed), "expected ErrClientClosed, got %v", err)
+	assert.True(t, errors.Is(c.Connect(), ErrClientClosed))
+	assert.NoError(t, c.Close(context.Background()))
+}
+
+// TestCloseWaitsForCallbacks verifies that Close waits for running
+// notification callbacks before it returns.
+func TestCloseWaitsForCallbacks(t *testing.T) {
+	var deletes atomic.Int32
+	router := newFakeRouter(t, closeTestHandler(0, &deletes))
+	c := NewClient(router.settings(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	started := make(chan struct{})
+	var finished atomic.Bool
+	_, err := c.SubscribeRaw(851, 0x4020, 0, 4, func(SubscriptionData) {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		finished.Store(true)
+	}, SubscriptionSettings{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	router.notify(851, 1, []byte{1, 0, 0, 0})
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected callback to start")
+	}
+
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.True(t, finished.Load(), "Close returned before the callback finished")
+}
+
+// TestCloseContextExpires verifies that Close gives up waiting when the
+// context expires and still tears the client down.
+func TestCloseContextExpires(t *testing.T) {
+	var deletes atomic.Int32
+	router := newFakeRouter(t, closeTestHandler(-1, &deletes))
+	c := NewClient(router.settings(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	readErr := make(chan error, 1)
+	go func() {
+		_, err := c.ReadRaw(851, 0x4020, 0, 4)
+		readErr <- err
+	}()
+	waitFor(t, time.Second, func() bool { return len(c.Debug().PendingRequests) == 1 }, "pending read")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := c.Close(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "expected DeadlineExceeded, got %v", err)
+	assert.Equal(t, ConnectionStateClosed, c.ConnectionState())
+
+	// The abandoned request is failed instead of running into its timeout.
+	select {
+	case err := <-readErr:
+		assert.True(t, errors.Is(err, ErrClientClosed), "expected ErrClientClosed, got %v", err)
+	case <-time.After(200 * time.Millisecond):
+		t.Fatalf("Expected pending read to be failed by Close")
+	}
+}
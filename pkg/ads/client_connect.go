@@ -13,29 +13,65 @@ import (
 )
 
 // Connect establishes a connection to the ADS router.
+// Connect may be called again after Disconnect or a connection loss to reconnect.
 func (c *Client) Connect() error {
+	c.connMutex.Lock()
+	if c.closing || c.connState == ConnectionStateClosed {
+		c.connMutex.Unlock()
+		return fmt.Errorf("Connect: %w", ErrClientClosed)
+	}
+	if c.connState == ConnectionStateConnecting || c.connState == ConnectionStateReconnecting {
+		c.connMutex.Unlock()
+		return fmt.Errorf("Connect: connection attempt already in progress")
+	}
+	previousState := c.connState
+	if c.wasConnected {
+		c.setConnectionStateLocked(ConnectionStateReconnecting)
+	} else {
+		c.setConnectionStateLocked(ConnectionStateConnecting)
+	}
+	c.connMutex.Unlock()
+
 	dialAddr := net.JoinHostPort(c.settings.RouterHost, strconv.Itoa(c.settings.RouterPort))
 	c.logger.Debug("Connect: Attempting to connect to router", "routerAddr", dialAddr)
 	conn, err := net.DialTimeout("tcp", dialAddr, c.settings.Timeout)
 	if err != nil {
 		c.logger.Error("Connect: Failed to dial router", "error", err)
+		c.abortConnect(previousState)
 		return err
 	}
-	c.conn = conn
 
-	// Reset the receive buffer so stale data from a previous connection cannot
-	// bleed into the new session's packet framing.
-	c.receiveBuffer.Reset()
-
-	if err := c.registerAdsPort(); err != nil {
-		if closeErr := c.conn.Close(); closeErr != nil {
+	localAddr, err := c.registerAdsPort(conn)
+	if err != nil {
+		if closeErr := conn.Close(); closeErr != nil {
 			c.logger.Error("Connect: Failed to close connection after port registration failure", "error", closeErr)
 		}
 		c.logger.Error("Connect: Failed to register ADS port", "error", err)
+		c.abortConnect(previousState)
 		return err
 	}
 	c.logger.Debug("Connect: ADS port registered.")
 
+	// Swap in the new connection. The receive lock is held so a stale receive
+	// goroutine cannot write into the buffer after it has been reset, and the
+	// reset ensures stale data from a previous connection cannot bleed into the
+	// new session's packet framing.
+	c.receiveMutex.Lock()
+	c.connMutex.Lock()
+	oldConn := c.conn
+	c.conn = conn
+	c.localAmsAddr = localAddr
+	c.connMutex.Unlock()
+	c.receiveBuffer.Reset()
+	c.receiveMutex.Unlock()
+
+	if oldConn != nil {
+		// Unblock the receive goroutine of the replaced connection.
+		if closeErr := oldConn.Close(); closeErr != nil {
+			c.logger.Debug("Connect: Failed to close replaced connection", "error", closeErr)
+		}
+	}
+
 	// Start receiving
 	go c.receive()
 
@@ -44,23 +80,31 @@ func (c *Client) Connect() error {
 		c.logger.Warn("Connect: PLC setup not complete", "error", err)
 	}
 
-	c.logger.Info("Connect: Successfully connected to ADS router", "localAMS", c.localAmsAddr.NetID, "port", c.localAmsAddr.Port)
+	c.logger.Info("Connect: Successfully connected to ADS router", "localAMS", localAddr.NetID, "port", localAddr.Port)
+
+	// Collect what the target supports before hooks and monitoring use it
+	if _, err := c.ProbeTarget(); err != nil {
+		c.logger.Warn("Connect: Failed to probe target", "error", err)
+	}
 
 	// Invoke OnConnect hook (synchronous)
-	if err := c.invokeConnectHook(c.localAmsAddr); err != nil {
+	if err := c.invokeConnectHook(localAddr); err != nil {
 		c.logger.Error("Connect: OnConnect hook failed, disconnecting", "error", err)
 		_ = c.Disconnect() // Clean up connection
 		return fmt.Errorf("connection hook failed: %w", err)
 	}
 
 	// Read initial state and start state monitoring
+	healthy := false
 	if initialState, err := c.ReadTcSystemState(); err != nil {
 		c.logger.Warn("Connect: Failed to read initial TwinCAT state", "error", err)
 	} else {
 		c.stateMutex.Lock()
 		c.currentState = initialState
+		c.consecutiveReadFailures = 0
 		c.stateMutex.Unlock()
 		c.logger.Info("Connect: Initial TwinCAT state", "state", initialState.AdsState.String())
+		healthy = initialState.AdsState == types.ADSStateRun
 
 		// Trigger OnStateChange hook for initial state (with oldState=nil)
 		// Called synchronously so the caller sees the state before Connect() returns.
@@ -69,53 +113,89 @@ func (c *Client) Connect() error {
 		})
 	}
 
+	c.connMutex.Lock()
+	c.wasConnected = true
+	if healthy {
+		c.setConnectionStateLocked(ConnectionStateConnected)
+	} else {
+		c.setConnectionStateLocked(ConnectionStateDegraded)
+	}
+	c.connMutex.Unlock()
+
 	// Start state poller if enabled
 	if c.settings.StatePollingInterval > 0 {
 		c.startStatePoller()
 	}
+	c.startStateNotification()
 
 	return nil
 }
 
+// abortConnect restores the state after a failed connection attempt.
+func (c *Client) abortConnect(previousState ConnectionState) {
+	c.connMutex.Lock()
+	defer c.connMutex.Unlock()
+	if c.conn != nil && (previousState == ConnectionStateConnected || previousState == ConnectionStateDegraded) {
+		// The previous connection is still in place.
+		c.setConnectionStateLocked(previousState)
+		return
+	}
+	c.setConnectionStateLocked(ConnectionStateDisconnected)
+}
+
 // Disconnect closes the connection to the ADS router.
 func (c *Client) Disconnect() error {
 	c.logger.Debug("Disconnect: Attempting to disconnect.")
-	if c.conn != nil {
-		// Stop state monitoring
-		c.stopStatePoller()
+	conn, _, err := c.activeConn()
+	if err != nil {
+		c.logger.Warn("Disconnect: No active connection to disconnect.")
+		return nil
+	}
 
-		// Clear cached state and failure counter
-		c.stateMutex.Lock()
-		c.currentState = nil
-		c.stateMutex.Unlock()
-		c.consecutiveReadFailures = 0
+	// Stop state monitoring
+	c.stopStatePoller()
+	c.stopStateNotification(c.send)
 
-		// Unsubscribe from all active subscriptions before disconnecting
-		if err := c.UnsubscribeAll(); err != nil {
-			c.logger.Warn("Disconnect: Error unsubscribing from all subscriptions", "error", err)
-		}
-		c.logger.Info("Disconnect: Unsubscribed from all active subscriptions.")
+	// Clear cached state and failure counter
+	c.stateMutex.Lock()
+	c.currentState = nil
+	c.consecutiveReadFailures = 0
+	c.stateMutex.Unlock()
+	c.clearPlcStates()
 
-		// Invoke OnDisconnect hook asynchronously (fire-and-forget)
-		go c.invokeHook("OnDisconnect", func() {
-			c.settings.OnDisconnect(c)
-		})
+	// Unsubscribe from all active subscriptions before disconnecting
+	if err := c.UnsubscribeAll(); err != nil {
+		c.logger.Warn("Disconnect: Error unsubscribing from all subscriptions", "error", err)
+	}
+	c.logger.Info("Disconnect: Unsubscribed from all active subscriptions.")
+
+	// Invoke OnDisconnect hook asynchronously (fire-and-forget)
+	go c.invokeHook("OnDisconnect", func() {
+		c.settings.OnDisconnect(c)
+	})
+
+	err = c.unregisterAdsPort(conn)
+	if err != nil {
+		c.logger.Error("Disconnect: Error unregistering ADS port", "error", err)
+	}
 
-		err := c.unregisterAdsPort()
-		if err != nil {
-			c.logger.Error("Disconnect: Error unregistering ADS port", "error", err)
+	// Release ownership before closing so the receive goroutine does not
+	// report the close as an unexpected connection loss.
+	c.connMutex.Lock()
+	if c.conn == conn {
+		c.conn = nil
+		c.wasConnected = false
+		if c.connState != ConnectionStateClosed {
+			c.setConnectionStateLocked(ConnectionStateDisconnected)
 		}
+	}
+	c.connMutex.Unlock()
 
-		defer func() {
-			if closeErr := c.conn.Close(); closeErr != nil {
-				c.logger.Error("Disconnect: Failed to close connection", "error", closeErr)
-			}
-		}()
-		c.logger.Info("Disconnect: Connection closed.")
-		return err
+	if closeErr := conn.Close(); closeErr != nil {
+		c.logger.Error("Disconnect: Failed to close connection", "error", closeErr)
 	}
-	c.logger.Warn("Disconnect: No active connection to disconnect.")
-	return nil
+	c.logger.Info("Disconnect: Connection closed.")
+	return err
 }
 
 // Connect to the PLC
@@ -145,8 +225,8 @@ func (c *Client) setupPlcConnection() error {
 	return nil
 }
 
-// registerAdsPort
-func (c *Client) registerAdsPort() error {
+// registerAdsPort registers a new ADS port on the router and returns the assigned local address.
+func (c *Client) registerAdsPort(conn net.Conn) (AmsAddress, error) {
 	c.logger.Debug("registerAdsPort: Creating AMS TCP header for port connection.")
 	amsTcpHeader := amsbuilder.BuildAmsTcpHeader(types.AMSTCPPortConnect, 2)
 	data := make([]byte, 2)
@@ -154,44 +234,52 @@ func (c *Client) registerAdsPort() error {
 	packet := append(amsTcpHeader, data...)
 
 	c.logger.Debug("registerAdsPort: Sending registration packet", "length", len(packet), "packet", packet)
-	if _, err := c.conn.Write(packet); err != nil {
+	if _, err := conn.Write(packet); err != nil {
 		c.logger.Error("registerAdsPort: Failed to write registration packet", "error", err)
-		return err
+		return AmsAddress{}, err
 	}
 
 	respAmsTcpHeader := make([]byte, constants.AMSTCPHeaderLength)
-	if _, err := c.conn.Read(respAmsTcpHeader); err != nil {
+	if _, err := conn.Read(respAmsTcpHeader); err != nil {
 		c.logger.Error("registerAdsPort: Failed to read response AMS TCP header", "error", err)
-		return err
+		return AmsAddress{}, err
 	}
 
 	c.logger.Debug("registerAdsPort: respAmsTcpHeader", "length", len(respAmsTcpHeader), "packet", respAmsTcpHeader)
 
 	length := binary.LittleEndian.Uint32(respAmsTcpHeader[2:6])
 	respData := make([]byte, length)
-	if _, err := c.conn.Read(respData); err != nil {
+	if _, err := conn.Read(respData); err != nil {
 		c.logger.Error("registerAdsPort: Failed to read response data", "error", err)
-		return err
+		return AmsAddress{}, err
 	}
 
 	c.logger.Debug("registerAdsPort: respData", "length", len(respData), "packet", respData)
-	c.localAmsAddr.NetID = utils.ByteArrayToAmsNetIdStr(respData[0:6])
-	c.localAmsAddr.Port = binary.LittleEndian.Uint16(respData[6:8])
+	if len(respData) < 8 {
+		c.logger.Error("registerAdsPort: Invalid response length", "length", len(respData), "expected", 8)
+		return AmsAddress{}, fmt.Errorf("registerAdsPort: invalid response length: %d", len(respData))
+	}
+	localAddr := AmsAddress{
+		NetID: utils.ByteArrayToAmsNetIdStr(respData[0:6]),
+		Port:  binary.LittleEndian.Uint16(respData[6:8]),
+	}
 
-	c.logger.Debug("registerAdsPort: Local AMS Address set", "netID", c.localAmsAddr.NetID, "port", c.localAmsAddr.Port)
+	c.logger.Debug("registerAdsPort: Local AMS Address set", "netID", localAddr.NetID, "port", localAddr.Port)
 	c.logger.Info("registerAdsPort: ADS port registration successful.")
-	return nil
+	return localAddr, nil
 }
 
-// unregisterAdsPort
-func (c *Client) unregisterAdsPort() error {
+// unregisterAdsPort releases the local ADS port on the router.
+func (c *Client) unregisterAdsPort(conn net.Conn) error {
 	c.logger.Debug("unregisterAdsPort: Creating AMS TCP header for port close.")
 	amsTcpHeader := amsbuilder.BuildAmsTcpHeader(types.AMSTCPPortClose, 2)
 	data := make([]byte, 2)
+	c.connMutex.RLock()
 	binary.LittleEndian.PutUint16(data, c.localAmsAddr.Port)
+	c.connMutex.RUnlock()
 	packet := append(amsTcpHeader, data...)
 
-	_, err := c.conn.Write(packet)
+	_, err := conn.Write(packet)
 	if err != nil {
 		c.logger.Error("unregisterAdsPort: Failed to write unregistration packet", "error", err)
 		return err
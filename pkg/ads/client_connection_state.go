@@ -0,0 +1,190 @@
+package ads
+
+import (
+	"fmt"
+	"net"
+)
+
+// ConnectionState is the lifecycle state of the client connection.
+type ConnectionState int
+
+const (
+	// ConnectionStateDisconnected means there is no connection to the router.
+	// This is the initial state and the state after Disconnect or a connection loss.
+	ConnectionStateDisconnected ConnectionState = iota
+	// ConnectionStateConnecting means the first Connect call is in progress.
+	ConnectionStateConnecting
+	// ConnectionStateConnected means the router connection is up and the target is healthy.
+	ConnectionStateConnected
+	// ConnectionStateDegraded means the router connection is up but the target is not
+	// fully operational: the last state read failed or TwinCAT is not in Run mode.
+	// Requests are still sent in this state.
+	ConnectionStateDegraded
+	// ConnectionStateReconnecting means Connect is in progress after an earlier
+	// connection was lost.
+	ConnectionStateReconnecting
+	// ConnectionStateClosed means the client was shut down and cannot be reused.
+	ConnectionStateClosed
+)
+
+// String returns the string representation of the connection state.
+func (s ConnectionState) String() string {
+	switch s {
+	case ConnectionStateDisconnected:
+		return "Disconnected"
+	case ConnectionStateConnecting:
+		return "Connecting"
+	case ConnectionStateConnected:
+		return "Connected"
+	case ConnectionStateDegraded:
+		return "Degraded"
+	case ConnectionStateReconnecting:
+		return "Reconnecting"
+	case ConnectionStateClosed:
+		return "Closed"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ConnectionState returns the current lifecycle state of the connection.
+func (c *Client) ConnectionState() ConnectionState {
+	c.connMutex.RLock()
+	defer c.connMutex.RUnlock()
+	return c.connState
+}
+
+// setConnectionStateLocked changes the connection state and queues the
+// OnStatusChange hook. The caller must hold connMutex.
+func (c *Client) setConnectionStateLocked(newState ConnectionState) {
+	oldState := c.connState
+	if oldState == newState {
+		return
+	}
+	c.connState = newState
+	c.logger.Info("Connection state changed", "from", oldState.String(), "to", newState.String())
+
+	if c.settings.OnStatusChange == nil {
+		return
+	}
+
+	// Hooks run on a single goroutine so they observe transitions in order.
+	c.statusEventsMutex.Lock()
+	c.statusEvents = append(c.statusEvents, statusEvent{newState: newState, oldState: oldState})
+	if !c.statusDispatching {
+		c.statusDispatching = true
+		go c.dispatchStatusEvents()
+	}
+	c.statusEventsMutex.Unlock()
+}
+
+// statusEvent is a queued OnStatusChange invocation.
+type statusEvent struct {
+	newState ConnectionState
+	oldState ConnectionState
+}
+
+// dispatchStatusEvents delivers queued status events until the queue is empty.
+func (c *Client) dispatchStatusEvents() {
+	for {
+		c.statusEventsMutex.Lock()
+		if len(c.statusEvents) == 0 {
+			c.statusDispatching = false
+			c.statusEventsMutex.Unlock()
+			return
+		}
+		event := c.statusEvents[0]
+		c.statusEvents = c.statusEvents[1:]
+		c.statusEventsMutex.Unlock()
+
+		c.invokeHook("OnStatusChange", func() {
+			c.settings.OnStatusChange(c, event.newState, event.oldState)
+		})
+	}
+}
+
+// updateTargetHealth moves between Connected and Degraded depending on the
+// outcome of the last state read. Other states are left untouched.
+// Notification subscriptions are suspended while the target is degraded.
+func (c *Client) updateTargetHealth(healthy bool) {
+	c.connMutex.Lock()
+	oldState := c.connState
+	if oldState != ConnectionStateConnected && oldState != ConnectionStateDegraded {
+		c.connMutex.Unlock()
+		return
+	}
+	if healthy {
+		c.setConnectionStateLocked(ConnectionStateConnected)
+	} else {
+		c.setConnectionStateLocked(ConnectionStateDegraded)
+	}
+	changed := c.connState != oldState
+	c.connMutex.Unlock()
+
+	if changed {
+		c.suspendNotifications(!healthy)
+	}
+}
+
+// activeConn returns the connection and local address to send requests with.
+// Returns ErrNotConnected when the client is not in a state that allows requests.
+func (c *Client) activeConn() (net.Conn, AmsAddress, error) {
+	c.connMutex.RLock()
+	defer c.connMutex.RUnlock()
+
+	if c.conn == nil {
+		return nil, AmsAddress{}, ErrNotConnected
+	}
+	switch c.connState {
+	case ConnectionStateDisconnected, ConnectionStateClosed:
+		return nil, AmsAddress{}, fmt.Errorf("%w (state: %s)", ErrNotConnected, c.connState)
+	}
+	return c.conn, c.localAmsAddr, nil
+}
+
+// ownsConn reports whether conn is still the active connection.
+func (c *Client) ownsConn(conn net.Conn) bool {
+	c.connMutex.RLock()
+	defer c.connMutex.RUnlock()
+	return c.conn == conn
+}
+
+// handleConnectionLost tears down the connection after an unexpected loss and
+// fires OnConnectionLost. If conn is no longer the active connection (it was
+// replaced by a reconnect or closed by Disconnect) nothing happens.
+// Pass nil to target whatever connection is currently active.
+func (c *Client) handleConnectionLost(conn net.Conn, err error) {
+	c.connMutex.Lock()
+	if conn == nil {
+		conn = c.conn
+	}
+	if conn == nil || c.conn != conn {
+		c.connMutex.Unlock()
+		c.logger.Info("handleConnectionLost: Connection already replaced or closed, skipping hook.")
+		return
+	}
+	c.conn = nil
+	c.setConnectionStateLocked(ConnectionStateDisconnected)
+	c.connMutex.Unlock()
+
+	// Closing unblocks the receive goroutine, which then exits as a stale owner.
+	if closeErr := conn.Close(); closeErr != nil {
+		c.logger.Debug("handleConnectionLost: Failed to close connection", "error", closeErr)
+	}
+	c.stopStatePoller()
+	c.stopStateNotification(c.transmit)
+	lostErr := fmt.Errorf("%w: %v", ErrNotConnected, err)
+	c.failPendingRequests(lostErr)
+	c.handleSubscriptionsLost(lostErr)
+	c.invokeConnectionLostHook(err)
+}
+
+// failPendingRequests completes all waiting requests with the given error
+// instead of letting them run into their timeout.
+func (c *Client) failPendingRequests(err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for _, req := range c.requests {
+		deliverResponse(req.ch, Response{Error: err})
+	}
+}
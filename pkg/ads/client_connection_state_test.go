@@ -0,0 +1,114 @@
+package ads
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConnectionStateLifecycle verifies the transitions of a normal
+// connect/disconnect cycle and the order of OnStatusChange events.
+func TestConnectionStateLifecycle(t *testing.T) {
+	router := newFakeRouter(t, nil)
+
+	var mu sync.Mutex
+	var events []ConnectionState
+	settings := router.settings()
+	settings.OnStatusChange = func(_ *Client, newState, _ ConnectionState) {
+		mu.Lock()
+		events = append(events, newState)
+		mu.Unlock()
+	}
+
+	c := NewClient(settings, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	assert.Equal(t, ConnectionStateDisconnected, c.ConnectionState())
+
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, ConnectionStateConnected, c.ConnectionState())
+
+	if err := c.Disconnect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, ConnectionStateDisconnected, c.ConnectionState())
+
+	expected := []ConnectionState{ConnectionStateConnecting, ConnectionStateConnected, ConnectionStateDisconnected}
+	waitFor(t, time.Second, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(events) == len(expected)
+	}, "status events")
+	mu.Lock()
+	assert.Equal(t, expected, events)
+	mu.Unlock()
+}
+
+// TestOperationsFailWhenNotConnected verifies that requests return ErrNotConnected.
+func TestOperationsFailWhenNotConnected(t *testing.T) {
+	c := newTestClient(ClientSettings{})
+
+	_, err := c.ReadRaw(851, 0x4020, 0, 4)
+	assert.True(t, errors.Is(err, ErrNotConnected), "expected ErrNotConnected, got %v", err)
+
+	err = c.SetTcSystemToRun()
+	assert.True(t, errors.Is(err, ErrNotConnected), "expected ErrNotConnected, got %v", err)
+}
+
+// TestConnectionLostAndReconnect verifies that a dropped connection moves the
+// client to Disconnected, fires OnConnectionLost once, and that the next
+// Connect reports Reconnecting.
+func TestConnectionLostAndReconnect(t *testing.T) {
+	router := newFakeRouter(t, nil)
+
+	lost := make(chan error, 4)
+	var mu sync.Mutex
+	var events []ConnectionState
+	settings := router.settings()
+	settings.OnConnectionLost = func(_ *Client, err error) { lost <- err }
+	settings.OnStatusChange = func(_ *Client, newState, _ ConnectionState) {
+		mu.Lock()
+		events = append(events, newState)
+		mu.Unlock()
+	}
+
+	c := NewClient(settings, nil)
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	router.dropConnection()
+	select {
+	case <-lost:
+	case <-time.After(time.Second):
+		t.Fatal("OnConnectionLost was not called")
+	}
+	assert.Equal(t, ConnectionStateDisconnected, c.ConnectionState())
+
+	_, err := c.ReadDeviceInfo()
+	assert.True(t, errors.Is(err, ErrNotConnected), "expected ErrNotConnected, got %v", err)
+
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error on reconnect, got %v", err)
+	}
+	assert.Equal(t, ConnectionStateConnected, c.ConnectionState())
+	_ = c.Disconnect()
+
+	waitFor(t, time.Second, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(events) == 6
+	}, "status events")
+	mu.Lock()
+	assert.Equal(t, []ConnectionState{
+		ConnectionStateConnecting, ConnectionStateConnected, ConnectionStateDisconnected,
+		ConnectionStateReconnecting, ConnectionStateConnected, ConnectionStateDisconnected,
+	}, events)
+	mu.Unlock()
+	assert.Len(t, lost, 0, "OnConnectionLost must fire only once and not on graceful disconnect")
+}
@@ -1,6 +1,7 @@
 package ads
 
 import (
+	"context"
 	"fmt"
 
 	adserrors "github.com/jarmocluyse/ads-go/pkg/ads/ads-errors"
@@ -10,6 +11,12 @@ import (
 
 // WriteControl writes control data to an ADS device.
 func (c *Client) WriteControl(adsState types.ADSState, deviceState uint16, targetPort uint16) error {
+	return c.writeControl(context.Background(), adsState, deviceState, targetPort)
+}
+
+// writeControl is WriteControl that stops waiting for the response when ctx
+// ends.
+func (c *Client) writeControl(ctx context.Context, adsState types.ADSState, deviceState uint16, targetPort uint16) error {
 	c.logger.Debug("WriteControl: Setting ADS state", "adsState", adsState.String(), "deviceState", fmt.Sprintf("0x%x", deviceState))
 
 	payload := adsrequests.BuildWriteControlRequest(uint16(adsState), deviceState)
@@ -19,7 +26,7 @@ func (c *Client) WriteControl(adsState types.ADSState, deviceState uint16, targe
 		TargetPort: targetPort,
 		Data:       payload,
 	}
-	respData, err := c.send(req)
+	respData, err := c.sendContext(ctx, req)
 	if err != nil {
 		c.logger.Error("WriteControl: Failed to send WriteControl command", "error", err)
 		return err
@@ -31,8 +31,8 @@ func (c *Client) SetTcSystemToConfig() error {
 // SetTcSystemToRun sets the TwinCAT system to run mode.
 func (c *Client) SetTcSystemToRun() error {
 	c.logger.Info("SetTcSystemToRun: Setting TwinCAT system to run mode")
-	if c.conn == nil {
-		return fmt.Errorf("SetTcSystemToRun: Client is not connected. Use Connect() to connect to the target first")
+	if _, _, err := c.activeConn(); err != nil {
+		return fmt.Errorf("SetTcSystemToRun: Use Connect() to connect to the target first: %w", err)
 	}
 
 	// Reading device state first as we don't want to change it (even though it's most probably 0)
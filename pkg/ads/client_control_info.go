@@ -11,37 +11,56 @@ import (
 // ReadTcSystemState reads the TwinCAT system state.
 func (c *Client) ReadTcSystemState() (*adsstateinfo.SystemState, error) {
 	c.logger.Debug("ReadTcSystemState: Reading TwinCAT system state.")
+	// Explicitly target SystemService port
+	return c.readState("ReadTcSystemState", types.ADSReservedPortSystemService)
+}
 
+// ReadPlcState reads the state of the PLC runtime on port (e.g. 851).
+// A runtime can be stopped while the TwinCAT system is in Run.
+// The result also updates the cached state returned by GetPlcState.
+func (c *Client) ReadPlcState(port uint16) (*adsstateinfo.SystemState, error) {
+	c.logger.Debug("ReadPlcState: Reading PLC runtime state.", "port", port)
+	state, err := c.readState("ReadPlcState", port)
+	if err != nil {
+		return nil, err
+	}
+	c.trackPlcPort(port)
+	c.applyPlcState(port, state, "ReadPlcState")
+	return state, nil
+}
+
+// readState sends a ReadState command to port.
+func (c *Client) readState(operationName string, port uint16) (*adsstateinfo.SystemState, error) {
 	req := AdsCommandRequest{
 		Command:    types.ADSCommandReadState,
-		TargetPort: types.ADSReservedPortSystemService, // Explicitly target SystemService port
+		TargetPort: port,
 		Data:       []byte{},
 	}
 	data, err := c.send(req)
 	if err != nil {
-		c.logger.Error("ReadTcSystemState: Failed to send ReadState command", "error", err)
+		c.logger.Error(operationName+": Failed to send ReadState command", "error", err)
 		return nil, err
 	}
 
-	c.logger.Debug("ReadTcSystemState: Received raw response data", "length", len(data), "data", fmt.Sprintf("%x", data))
+	c.logger.Debug(operationName+": Received raw response data", "length", len(data), "data", fmt.Sprintf("%x", data))
 	if len(data) < 8 {
-		c.logger.Error("ReadTcSystemState: Invalid response length", "length", len(data), "expected", "at least 8")
+		c.logger.Error(operationName+": Invalid response length", "length", len(data), "expected", "at least 8")
 		return nil, fmt.Errorf("invalid response length: %d", len(data))
 	}
 
 	payload, err := adserrors.StripAdsError(data)
 	if err != nil {
-		c.logger.Error("ReadTcSystemState: ADS error received", "error", err)
+		c.logger.Error(operationName+": ADS error received", "error", err)
 		return nil, err
 	}
 
 	state, err := adsstateinfo.ParseSystemState(payload)
 	if err != nil {
-		c.logger.Error("ReadTcSystemState: Failed to parse system state", "error", err)
+		c.logger.Error(operationName+": Failed to parse system state", "error", err)
 		return nil, err
 	}
 
-	c.logger.Info("ReadTcSystemState: Successfully parsed TwinCAT system state response", "response", state)
+	c.logger.Info(operationName+": Successfully parsed state response", "port", port, "response", state)
 	return &state, nil
 }
 
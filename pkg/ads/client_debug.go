@@ -0,0 +1,187 @@
+package ads
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	adsstateinfo "github.com/jarmocluyse/ads-go/pkg/ads/ads-stateinfo"
+)
+
+// DebugSnapshot is a point-in-time view of the client internals.
+// It is meant for troubleshooting a misbehaving client in production and is
+// safe to encode as JSON.
+type DebugSnapshot struct {
+	TakenAt                time.Time                            `json:"takenAt"`                // when the snapshot was taken
+	TargetNetID            string                               `json:"targetNetId"`            // configured target AMS net id
+	LocalAmsAddr           AmsAddress                           `json:"localAmsAddr"`           // local AMS address assigned by the router
+	PendingRequests        []DebugPendingRequest                `json:"pendingRequests"`        // requests waiting for a response, oldest first
+	Subscriptions          []DebugSubscription                  `json:"subscriptions"`          // active subscriptions, ordered by handle
+	SystemState            *adsstateinfo.SystemState            `json:"systemState"`            // cached TwinCAT system state (nil if unknown)
+	PlcStates              map[uint16]*adsstateinfo.SystemState `json:"plcStates"`              // cached state per tracked PLC runtime port (nil if unknown)
+	ExtendedStateSupported *bool                                `json:"extendedStateSupported"` // nil = unknown, true/false = tested
+	LastRestartIndex       *uint16                              `json:"lastRestartIndex"`       // last seen restart index (nil if unknown)
+	TargetInfo             *TargetInfo                          `json:"targetInfo"`             // probed target capabilities (nil if not probed)
+	ReceiveBufferLen       int                                  `json:"receiveBufferLen"`       // unprocessed bytes in the receive buffer
+	ReceiveBufferCap       int                                  `json:"receiveBufferCap"`       // allocated size of the receive buffer
+}
+
+// DebugPendingRequest describes a request that is waiting for its response.
+type DebugPendingRequest struct {
+	InvokeID uint32        `json:"invokeId"` // invoke id of the request
+	SentAt   time.Time     `json:"sentAt"`   // time the invoke id was assigned
+	Age      time.Duration `json:"age"`      // how long the request has been waiting
+}
+
+// DebugSubscription describes an active subscription.
+type DebugSubscription struct {
+	Handle            uint32        `json:"handle"`            // notification handle assigned by the PLC
+	Port              uint16        `json:"port"`              // target ADS port
+	Path              string        `json:"path,omitempty"`    // symbol path (empty for raw subscriptions)
+	IsRaw             bool          `json:"isRaw"`             // subscribed through SubscribeRaw
+	CycleTime         time.Duration `json:"cycleTime"`         // subscription cycle time
+	MaxDelay          time.Duration `json:"maxDelay"`          // subscription max delay
+	SendOnChange      bool          `json:"sendOnChange"`      // on-change (true) or cyclic (false)
+	TransmissionMode  string        `json:"transmissionMode"`  // effective transmission mode
+	ClientSide        bool          `json:"clientSide"`        // no own PLC notification (handle is local)
+	Polled            bool          `json:"polled"`            // read by the client every cycle time
+	State             string        `json:"state"`             // Active, Suspended or Dead
+	ParentHandle      uint32        `json:"parentHandle"`      // notification shared by a multiplexed member (0 otherwise)
+	LastNotification  time.Time     `json:"lastNotification"`  // zero if nothing was received yet
+	NotificationCount uint64        `json:"notificationCount"` // number of samples received
+	DroppedSamples    uint64        `json:"droppedSamples"`    // samples discarded by the overflow policy
+	FilteredSamples   uint64        `json:"filteredSamples"`   // samples suppressed by the filter
+}
+
+// Debug returns a snapshot of the client internals: pending requests, active
+// subscriptions, cached state and buffer usage.
+//
+// Example:
+//
+//	snap := client.Debug()
+//	for _, req := range snap.PendingRequests {
+//	    fmt.Printf("invoke %d waiting for %s\n", req.InvokeID, req.Age)
+//	}
+func (c *Client) Debug() DebugSnapshot {
+	now := time.Now()
+	snap := DebugSnapshot{
+		TakenAt:          now,
+		TargetNetID:      c.settings.TargetNetID,
+		PendingRequests:  []DebugPendingRequest{},
+		Subscriptions:    []DebugSubscription{},
+		ReceiveBufferLen: int(c.receiveBufferLen.Load()),
+		ReceiveBufferCap: int(c.receiveBufferCap.Load()),
+	}
+
+	c.connMutex.RLock()
+	snap.LocalAmsAddr = c.localAmsAddr
+	c.connMutex.RUnlock()
+
+	c.mutex.Lock()
+	for id, req := range c.requests {
+		snap.PendingRequests = append(snap.PendingRequests, DebugPendingRequest{
+			InvokeID: id,
+			SentAt:   req.sentAt,
+			Age:      now.Sub(req.sentAt),
+		})
+	}
+	c.mutex.Unlock()
+	sort.Slice(snap.PendingRequests, func(i, j int) bool {
+		a, b := snap.PendingRequests[i], snap.PendingRequests[j]
+		if a.SentAt.Equal(b.SentAt) {
+			return a.InvokeID < b.InvokeID
+		}
+		return a.SentAt.Before(b.SentAt)
+	})
+
+	for _, sub := range c.subscriptionList() {
+		snap.Subscriptions = append(snap.Subscriptions, debugSubscription(sub))
+	}
+	sort.Slice(snap.Subscriptions, func(i, j int) bool {
+		return snap.Subscriptions[i].Handle < snap.Subscriptions[j].Handle
+	})
+
+	c.stateMutex.RLock()
+	if c.currentState != nil {
+		state := *c.currentState
+		snap.SystemState = &state
+	}
+	c.stateMutex.RUnlock()
+
+	c.plcStateMutex.Lock()
+	snap.PlcStates = make(map[uint16]*adsstateinfo.SystemState, len(c.plcStates))
+	for port, state := range c.plcStates {
+		if state != nil {
+			copied := *state
+			state = &copied
+		}
+		snap.PlcStates[port] = state
+	}
+	c.plcStateMutex.Unlock()
+
+	c.extendedStateMutex.RLock()
+	if c.extendedStateSupported != nil {
+		supported := *c.extendedStateSupported
+		snap.ExtendedStateSupported = &supported
+	}
+	if c.lastRestartIndex != nil {
+		index := *c.lastRestartIndex
+		snap.LastRestartIndex = &index
+	}
+	c.extendedStateMutex.RUnlock()
+
+	snap.TargetInfo = c.TargetInfo()
+
+	return snap
+}
+
+// debugSubscription converts an active subscription to its debug representation.
+func debugSubscription(sub *ActiveSubscription) DebugSubscription {
+	path := ""
+	if sub.Symbol != nil {
+		path = sub.Symbol.Name
+	}
+	return DebugSubscription{
+		Handle:            sub.Handle,
+		Port:              sub.Port,
+		Path:              path,
+		IsRaw:             sub.IsRaw,
+		CycleTime:         sub.Settings.CycleTime,
+		MaxDelay:          sub.Settings.MaxDelay,
+		SendOnChange:      sub.Settings.SendOnChange,
+		TransmissionMode:  sub.Settings.EffectiveTransmissionMode().String(),
+		ClientSide:        sub.clientSide,
+		Polled:            sub.poll != nil,
+		State:             sub.State().String(),
+		ParentHandle:      sub.parentHandle(),
+		LastNotification:  sub.LastNotification(),
+		NotificationCount: sub.NotificationCount(),
+		DroppedSamples:    sub.DroppedSamples(),
+		FilteredSamples:   sub.FilteredSamples(),
+	}
+}
+
+// DebugHandler returns an http.Handler that serves the Debug snapshot as JSON.
+// Mount it on an internal-only listener, it exposes the target address and
+// the symbol paths of all subscriptions.
+//
+// Example:
+//
+//	http.Handle("/debug/ads", client.DebugHandler())
+func (c *Client) DebugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(c.Debug()); err != nil {
+			c.logger.Error("DebugHandler: Failed to encode debug snapshot", "error", err)
+		}
+	})
+}
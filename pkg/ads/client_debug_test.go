@@ -0,0 +1,70 @@
+package ads
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	adssymbol "github.com/jarmocluyse/ads-go/pkg/ads/ads-symbol"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDebugSnapshot verifies that Debug reports pending requests and
+// subscriptions in a stable order.
+func TestDebugSnapshot(t *testing.T) {
+	c := newTestClient(ClientSettings{TargetNetID: "1.2.3.4.1.1"})
+
+	first, _ := c.getInvokeID()
+	second, _ := c.getInvokeID()
+
+	sub := &ActiveSubscription{
+		Handle:   7,
+		Port:     851,
+		Symbol:   &adssymbol.AdsSymbol{Name: "GVL.Counter"},
+		Settings: SubscriptionSettings{CycleTime: 100 * time.Millisecond, SendOnChange: true},
+	}
+	sub.recordNotification(time.Now())
+	c.subscriptions[sub.Handle] = sub
+	c.subscriptions[3] = &ActiveSubscription{Handle: 3, Port: 851, IsRaw: true}
+
+	snap := c.Debug()
+
+	assert.Equal(t, "1.2.3.4.1.1", snap.TargetNetID)
+	if len(snap.PendingRequests) != 2 {
+		t.Fatalf("Expected 2 pending requests, got %d", len(snap.PendingRequests))
+	}
+	assert.Equal(t, first, snap.PendingRequests[0].InvokeID)
+	assert.Equal(t, second, snap.PendingRequests[1].InvokeID)
+
+	if len(snap.Subscriptions) != 2 {
+		t.Fatalf("Expected 2 subscriptions, got %d", len(snap.Subscriptions))
+	}
+	assert.Equal(t, uint32(3), snap.Subscriptions[0].Handle)
+	assert.True(t, snap.Subscriptions[0].LastNotification.IsZero())
+	assert.Equal(t, "GVL.Counter", snap.Subscriptions[1].Path)
+	assert.Equal(t, uint64(1), snap.Subscriptions[1].NotificationCount)
+	assert.Nil(t, snap.SystemState)
+}
+
+// TestDebugHandler verifies the JSON endpoint.
+func TestDebugHandler(t *testing.T) {
+	c := newTestClient(ClientSettings{TargetNetID: "1.2.3.4.1.1"})
+
+	rec := httptest.NewRecorder()
+	c.DebugHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/ads", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var snap DebugSnapshot
+	if err := json.Unmarshal(rec.Body.Bytes(), &snap); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+	assert.Equal(t, "1.2.3.4.1.1", snap.TargetNetID)
+
+	rec = httptest.NewRecorder()
+	c.DebugHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/ads", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -0,0 +1,263 @@
+package ads
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	amsbuilder "github.com/jarmocluyse/ads-go/pkg/ads/ams-builder"
+	amsheader "github.com/jarmocluyse/ads-go/pkg/ads/ams-header"
+	"github.com/jarmocluyse/ads-go/pkg/ads/constants"
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+)
+
+// fakeRouterHandler answers an ADS command. The returned payload is sent back
+// as-is, so it must start with the ADS error code where the command has one.
+// Returning nil drops the request (the client runs into its timeout).
+type fakeRouterHandler func(cmd types.ADSCommand, port uint16, data []byte) []byte
+
+// fakeRouter is a minimal in-process AMS router used to exercise the client
+// without a TwinCAT target. It accepts one connection at a time.
+type fakeRouter struct {
+	t        *testing.T
+	listener net.Listener
+	handler  fakeRouterHandler
+
+	mu         sync.Mutex
+	conn       net.Conn
+	portErrors map[uint16]uint32 // AMS header error code per target port
+}
+
+// fakeTargetNetID is the AMS net id the fake router answers for.
+const fakeTargetNetID = "10.0.0.1.1.1"
+
+// newFakeRouter starts a fake router. handler may be nil to use defaultFakeHandler.
+func newFakeRouter(t *testing.T, handler fakeRouterHandler) *fakeRouter {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	if handler == nil {
+		handler = defaultFakeHandler
+	}
+	r := &fakeRouter{t: t, listener: listener, handler: handler}
+	go r.acceptLoop()
+	t.Cleanup(r.close)
+	return r
+}
+
+// settings returns client settings pointing at the fake router.
+func (r *fakeRouter) settings() ClientSettings {
+	addr := r.listener.Addr().(*net.TCPAddr)
+	return ClientSettings{
+		TargetNetID:          fakeTargetNetID,
+		RouterHost:           "127.0.0.1",
+		RouterPort:           addr.Port,
+		Timeout:              500 * time.Millisecond,
+		StatePollingInterval: time.Hour, // effectively off, tests poll explicitly
+	}
+}
+
+// defaultFakeHandler answers ReadState with Run and everything else with success.
+func defaultFakeHandler(cmd types.ADSCommand, port uint16, data []byte) []byte {
+	switch cmd {
+	case types.ADSCommandReadState:
+		resp := make([]byte, 8)
+		binary.LittleEndian.PutUint16(resp[4:6], uint16(types.ADSStateRun))
+		return resp
+	case types.ADSCommandReadDeviceInfo:
+		resp := make([]byte, 24)
+		copy(resp[8:], "FakeRouter")
+		return resp
+	case types.ADSCommandReadWrite:
+		if resp := fakeSumCommandResponse(data); resp != nil {
+			return resp
+		}
+		return make([]byte, 8) // error code + zero length
+	case types.ADSCommandRead:
+		return make([]byte, 8) // error code + zero length
+	default:
+		return make([]byte, 4)
+	}
+}
+
+// fakeNotificationHandle hands out notification handles for AddDevNote sum commands.
+var fakeNotificationHandle atomic.Uint32
+
+// fakeSumCommandResponse answers AddDevNote and DelDevNote sum commands with
+// success for every item. Returns nil for other ReadWrite requests.
+func fakeSumCommandResponse(data []byte) []byte {
+	indexGroup, count := sumCommandHeader(data)
+	var items []byte
+	switch indexGroup {
+	case types.ADSReservedIndexGroupSumCommandAddDevNote:
+		items = make([]byte, 8*count)
+		for n := range count {
+			binary.LittleEndian.PutUint32(items[n*8+4:], 1000+fakeNotificationHandle.Add(1))
+		}
+	case types.ADSReservedIndexGroupSumCommandDelDevNote:
+		items = make([]byte, 4*count)
+	default:
+		return nil
+	}
+	resp := make([]byte, 8, 8+len(items))
+	binary.LittleEndian.PutUint32(resp[4:8], uint32(len(items)))
+	return append(resp, items...)
+}
+
+// sumCommandHeader returns the index group and item count of a ReadWrite request.
+func sumCommandHeader(data []byte) (types.ADSReservedIndexGroup, int) {
+	if len(data) < 16 {
+		return 0, 0
+	}
+	return types.ADSReservedIndexGroup(binary.LittleEndian.Uint32(data[0:4])), int(binary.LittleEndian.Uint32(data[4:8]))
+}
+
+func (r *fakeRouter) acceptLoop() {
+	for {
+		conn, err := r.listener.Accept()
+		if err != nil {
+			return
+		}
+		r.mu.Lock()
+		r.conn = conn
+		r.mu.Unlock()
+		go r.serve(conn)
+	}
+}
+
+func (r *fakeRouter) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	header := make([]byte, constants.AMSTCPHeaderLength)
+	for {
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		length := binary.LittleEndian.Uint32(header[2:6])
+		body := make([]byte, length)
+		if _, err := io.ReadFull(conn, body); err != nil {
+			return
+		}
+
+		switch types.AMSHeaderFlag(binary.LittleEndian.Uint16(header[0:2])) {
+		case types.AMSTCPPortConnect:
+			resp := amsbuilder.BuildAmsTcpHeader(types.AMSTCPPortConnect, 8)
+			resp = append(resp, 10, 0, 0, 2, 1, 1, 0x39, 0x80) // 10.0.0.2.1.1:32825
+			_, _ = conn.Write(resp)
+		case types.AMSTCPPortClose:
+			return
+		case types.AMSTCPPortAMSCommand:
+			packet, err := amsheader.ParsePacket(append(header, body...))
+			if err != nil {
+				r.t.Errorf("fake router: invalid packet: %v", err)
+				return
+			}
+			if code := r.portError(packet.TargetPort); code != 0 {
+				r.write(conn, packet.Command, packet.TargetPort, packet.SourceNetID, packet.SourcePort, packet.InvokeID, nil, code)
+				continue
+			}
+			payload := r.handler(packet.Command, packet.TargetPort, packet.Data)
+			if payload == nil {
+				continue
+			}
+			r.write(conn, packet.Command, packet.TargetPort, packet.SourceNetID, packet.SourcePort, packet.InvokeID, payload, 0)
+		}
+	}
+}
+
+// setPortError makes the router answer every command to port with code in the
+// AMS header, as a router does for unknown ports (code 0 clears it).
+func (r *fakeRouter) setPortError(port uint16, code uint32) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.portErrors == nil {
+		r.portErrors = map[uint16]uint32{}
+	}
+	r.portErrors[port] = code
+}
+
+func (r *fakeRouter) portError(port uint16) uint32 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.portErrors[port]
+}
+
+// write sends a response packet from the given target port back to the client.
+func (r *fakeRouter) write(conn net.Conn, cmd types.ADSCommand, fromPort uint16, toNetID string, toPort uint16, invokeID uint32, payload []byte, errorCode uint32) {
+	target := AmsAddress{NetID: toNetID, Port: toPort}
+	source := AmsAddress{NetID: fakeTargetNetID, Port: fromPort}
+	amsHeader, err := amsbuilder.BuildAmsHeader(target, source, cmd, uint32(len(payload)), invokeID)
+	if err != nil {
+		r.t.Errorf("fake router: failed to build header: %v", err)
+		return
+	}
+	binary.LittleEndian.PutUint16(amsHeader[18:20], uint16(types.ADSStateFlagResponse|types.ADSStateFlagAdsCommand))
+	binary.LittleEndian.PutUint32(amsHeader[24:28], errorCode)
+	packet := amsbuilder.BuildAmsTcpHeader(types.AMSTCPPortAMSCommand, uint32(len(amsHeader)+len(payload)))
+	packet = append(packet, amsHeader...)
+	packet = append(packet, payload...)
+	_, _ = conn.Write(packet)
+}
+
+// notify sends a device notification with one sample for handle to the client.
+func (r *fakeRouter) notify(port uint16, handle uint32, data []byte) {
+	r.mu.Lock()
+	conn := r.conn
+	r.mu.Unlock()
+	if conn == nil {
+		r.t.Errorf("fake router: no client connected")
+		return
+	}
+	r.write(conn, types.ADSCommandNotification, port, "10.0.0.2.1.1", 32825, 0, buildNotification(handle, data), 0)
+}
+
+// buildNotification encodes a notification stream with one stamp and one sample.
+func buildNotification(handle uint32, data []byte) []byte {
+	sample := make([]byte, 8+len(data))
+	binary.LittleEndian.PutUint32(sample[0:4], handle)
+	binary.LittleEndian.PutUint32(sample[4:8], uint32(len(data)))
+	copy(sample[8:], data)
+
+	stamp := make([]byte, 12, 12+len(sample))
+	binary.LittleEndian.PutUint64(stamp[0:8], 133000000000000000) // FILETIME in 2022
+	binary.LittleEndian.PutUint32(stamp[8:12], 1)
+	stamp = append(stamp, sample...)
+
+	payload := make([]byte, 8, 8+len(stamp))
+	binary.LittleEndian.PutUint32(payload[0:4], uint32(4+len(stamp)))
+	binary.LittleEndian.PutUint32(payload[4:8], 1)
+	return append(payload, stamp...)
+}
+
+// dropConnection closes the active client connection from the router side.
+func (r *fakeRouter) dropConnection() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.conn != nil {
+		_ = r.conn.Close()
+		r.conn = nil
+	}
+}
+
+func (r *fakeRouter) close() {
+	_ = r.listener.Close()
+	r.dropConnection()
+}
+
+// waitFor polls cond until it returns true or the timeout expires.
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool, msg string) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for: %s", msg)
+}
@@ -0,0 +1,204 @@
+package ads
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+	"github.com/jarmocluyse/ads-go/pkg/ads/utils"
+)
+
+// Logger notification address: all messages of the ADS logger.
+const (
+	loggerIndexGroup  uint32 = 1
+	loggerIndexOffset uint32 = 0xFFFF
+	loggerMessageSize uint32 = 1024
+)
+
+// loggerHeaderSize is the size of a logger message before the text.
+const loggerHeaderSize = 24
+
+// Message type flags of ADSLOGSTR and friends (ADSLOG_MSGTYPE_*).
+const (
+	LogFlagHint     uint32 = 0x01
+	LogFlagWarning  uint32 = 0x02
+	LogFlagError    uint32 = 0x04
+	LogFlagLog      uint32 = 0x10
+	LogFlagMsgBox   uint32 = 0x20
+	LogFlagResource uint32 = 0x40
+	LogFlagString   uint32 = 0x80
+)
+
+// LogSeverity is the severity of a logger message.
+type LogSeverity int
+
+const (
+	LogSeverityInfo LogSeverity = iota
+	LogSeverityWarning
+	LogSeverityError
+)
+
+// String returns the string representation of the severity.
+func (s LogSeverity) String() string {
+	switch s {
+	case LogSeverityInfo:
+		return "Info"
+	case LogSeverityWarning:
+		return "Warning"
+	case LogSeverityError:
+		return "Error"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// LogMessage is a message of the TwinCAT ADS logger, e.g. from ADSLOGSTR.
+type LogMessage struct {
+	// Timestamp is when the message was logged on the target.
+	Timestamp time.Time
+
+	// Severity is derived from the message type flags.
+	Severity LogSeverity
+
+	// Flags are the raw message type flags (LogFlagHint, LogFlagWarning, ...).
+	Flags uint32
+
+	// Source is the AMS address of the sender (e.g. port 851 for a PLC runtime).
+	Source AmsAddress
+
+	// Text is the message text.
+	Text string
+
+	// Err is only set on the last message before the channel is closed and
+	// tells why, like SubscriptionData.Err.
+	Err error
+}
+
+// SubscribeLogMessages subscribes to the messages of the TwinCAT ADS logger
+// (port 100) and delivers them on a channel. This includes ADSLOGSTR,
+// ADSLOGDINT and ADSLOGLREAL messages of all PLC runtimes, and the
+// Tc3_EventLogger messages that the event logger mirrors to the ADS logger.
+// The native event logger protocol (ports 130-132) is not decoded.
+//
+// The channel is closed like the one of SubscribeValueChan; the last message
+// carries the reason in Err. Settings default to on-change notifications with
+// a 10ms cycle time.
+//
+// Example:
+//
+//	messages, _, err := client.SubscribeLogMessages(ctx, ads.SubscriptionSettings{})
+//	if err != nil {
+//	    return err
+//	}
+//	for msg := range messages {
+//	    if msg.Err != nil {
+//	        return msg.Err
+//	    }
+//	    fmt.Printf("%s [%s] port %d: %s\n", msg.Timestamp, msg.Severity, msg.Source.Port, msg.Text)
+//	}
+func (c *Client) SubscribeLogMessages(ctx context.Context, settings SubscriptionSettings) (<-chan LogMessage, *ActiveSubscription, error) {
+	if settings.CycleTime == 0 {
+		settings.CycleTime = 10 * time.Millisecond
+	}
+	if settings.TransmissionMode == types.ADSTransModeNone {
+		settings.SendOnChange = true
+	}
+
+	raw, sub, err := c.subscribeChan(ctx, func(callback SubscriptionCallback, onTerminate func(err error)) (*ActiveSubscription, error) {
+		return c.addSubscription(subscriptionSpec{
+			port:        uint16(types.ADSReservedPortLogger),
+			indexGroup:  loggerIndexGroup,
+			indexOffset: loggerIndexOffset,
+			size:        loggerMessageSize,
+			callback:    callback,
+			settings:    settings,
+			isRaw:       true,
+			onTerminate: onTerminate,
+		})
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("SubscribeLogMessages: %w", err)
+	}
+
+	out := make(chan LogMessage)
+	go func() {
+		defer close(out)
+		for data := range raw {
+			var msg LogMessage
+			if data.Err != nil {
+				msg.Err = data.Err
+			} else {
+				parsed, perr := parseLogMessage(data.RawValue)
+				if perr != nil {
+					c.logger.Warn("SubscribeLogMessages: Invalid logger message", "length", len(data.RawValue), "error", perr)
+					continue
+				}
+				msg = parsed
+				if msg.Timestamp.IsZero() {
+					msg.Timestamp = data.Timestamp
+				}
+			}
+
+			// Like sendTerminalValue: once ctx is done only a waiting consumer
+			// gets the message, raw is drained so the subscription can shut down.
+			select {
+			case out <- msg:
+				continue
+			default:
+			}
+			select {
+			case out <- msg:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return out, sub, nil
+}
+
+// parseLogMessage decodes a logger notification:
+//
+//	[0..7]   Timestamp (FILETIME)
+//	[8..11]  Message type flags (uint32)
+//	[12..17] Sender AMS net id
+//	[18..19] Sender port (uint16)
+//	[20..23] Text length including the terminator (uint32)
+//	[24..]   Text (null-terminated)
+func parseLogMessage(data []byte) (LogMessage, error) {
+	if len(data) < loggerHeaderSize {
+		return LogMessage{}, fmt.Errorf("message too short: %d bytes", len(data))
+	}
+	textLen := int(binary.LittleEndian.Uint32(data[20:24]))
+	if textLen > len(data)-loggerHeaderSize {
+		textLen = len(data) - loggerHeaderSize
+	}
+
+	var timestamp time.Time
+	if ft := binary.LittleEndian.Uint64(data[0:8]); ft != 0 {
+		timestamp = filetimeToTime(ft)
+	}
+	flags := binary.LittleEndian.Uint32(data[8:12])
+	return LogMessage{
+		Timestamp: timestamp,
+		Severity:  logSeverity(flags),
+		Flags:     flags,
+		Source: AmsAddress{
+			NetID: utils.ByteArrayToAmsNetIdStr(data[12:18]),
+			Port:  binary.LittleEndian.Uint16(data[18:20]),
+		},
+		Text: utils.DecodePlcStringBuffer(data[loggerHeaderSize : loggerHeaderSize+textLen]),
+	}, nil
+}
+
+// logSeverity maps message type flags to a severity.
+func logSeverity(flags uint32) LogSeverity {
+	switch {
+	case flags&LogFlagError != 0:
+		return LogSeverityError
+	case flags&LogFlagWarning != 0:
+		return LogSeverityWarning
+	default:
+		return LogSeverityInfo
+	}
+}
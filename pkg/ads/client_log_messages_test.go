@@ -0,0 +1,89 @@
+package ads
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// logMessagePayload builds a logger notification.
+func logMessagePayload(flags uint32, port uint16, text string) []byte {
+	data := make([]byte, loggerHeaderSize, loggerHeaderSize+len(text)+1)
+	ft := uint64(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC).Unix()+11644473600) * 1e7
+	binary.LittleEndian.PutUint64(data[0:8], ft)
+	binary.LittleEndian.PutUint32(data[8:12], flags)
+	copy(data[12:18], []byte{10, 0, 0, 1, 1, 1})
+	binary.LittleEndian.PutUint16(data[18:20], port)
+	binary.LittleEndian.PutUint32(data[20:24], uint32(len(text)+1))
+	data = append(data, text...)
+	return append(data, 0)
+}
+
+func TestParseLogMessage(t *testing.T) {
+	msg, err := parseLogMessage(logMessagePayload(LogFlagError|LogFlagString, 851, "Axis 1 following error"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, LogSeverityError, msg.Severity)
+	assert.Equal(t, AmsAddress{NetID: "10.0.0.1.1.1", Port: 851}, msg.Source)
+	assert.Equal(t, "Axis 1 following error", msg.Text)
+	assert.True(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC).Equal(msg.Timestamp), "got %v", msg.Timestamp)
+
+	msg, _ = parseLogMessage(logMessagePayload(LogFlagWarning, 852, "low pressure"))
+	assert.Equal(t, LogSeverityWarning, msg.Severity)
+	msg, _ = parseLogMessage(logMessagePayload(LogFlagHint, 852, "started"))
+	assert.Equal(t, LogSeverityInfo, msg.Severity)
+
+	_, err = parseLogMessage(make([]byte, 10))
+	assert.Error(t, err)
+}
+
+// TestSubscribeLogMessages verifies that logger notifications are delivered
+// on the channel and that the channel closes with the context.
+func TestSubscribeLogMessages(t *testing.T) {
+	router := newFakeRouter(t, func(cmd types.ADSCommand, port uint16, data []byte) []byte {
+		if cmd == types.ADSCommandAddNotification && port == uint16(types.ADSReservedPortLogger) {
+			assert.Equal(t, loggerIndexGroup, binary.LittleEndian.Uint32(data[0:4]))
+			resp := make([]byte, 8)
+			binary.LittleEndian.PutUint32(resp[4:8], 9)
+			return resp
+		}
+		return defaultFakeHandler(cmd, port, data)
+	})
+	c := NewClient(router.settings(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer func() { _ = c.Disconnect() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	messages, sub, err := c.SubscribeLogMessages(ctx, SubscriptionSettings{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, types.ADSTransModeOnChange, sub.Settings.EffectiveTransmissionMode())
+
+	router.notify(uint16(types.ADSReservedPortLogger), 9, []byte{1, 2}) // invalid, skipped
+	router.notify(uint16(types.ADSReservedPortLogger), 9, logMessagePayload(LogFlagWarning, 851, "Tank level low"))
+	select {
+	case msg := <-messages:
+		assert.Equal(t, "Tank level low", msg.Text)
+		assert.Equal(t, LogSeverityWarning, msg.Severity)
+		assert.Equal(t, uint16(851), msg.Source.Port)
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting for the log message")
+	}
+
+	cancel()
+	var last LogMessage
+	for msg := range messages {
+		last = msg
+	}
+	assert.ErrorIs(t, last.Err, context.Canceled)
+}
@@ -0,0 +1,107 @@
+package ads
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+)
+
+// Device states sent with a Reset control to select a reset cold or a reset
+// origin.
+const (
+	plcResetColdDeviceState   uint16 = 0
+	plcResetOriginDeviceState uint16 = 1
+)
+
+// plcControlPollInterval is how often the runtime state is read while waiting
+// for a control command to take effect.
+const plcControlPollInterval = 50 * time.Millisecond
+
+// StartPlc starts the PLC runtime on port (e.g. 851) and waits until it is in Run.
+// Other runtimes and the TwinCAT system are not affected.
+func (c *Client) StartPlc(port uint16) error {
+	return c.controlPlc("StartPlc", port, types.ADSStateRun, types.ADSStateRun, nil)
+}
+
+// StopPlc stops the PLC runtime on port and waits until it is in Stop.
+func (c *Client) StopPlc(port uint16) error {
+	return c.controlPlc("StopPlc", port, types.ADSStateStop, types.ADSStateStop, nil)
+}
+
+// ResetPlcCold resets the PLC runtime on port (reset cold: all variables
+// except RETAIN and PERSISTENT are initialized) and waits until it is in Stop.
+func (c *Client) ResetPlcCold(port uint16) error {
+	deviceState := plcResetColdDeviceState
+	return c.controlPlc("ResetPlcCold", port, types.ADSStateReset, types.ADSStateStop, &deviceState)
+}
+
+// ResetPlcOrigin resets the PLC runtime on port to its origin (including
+// RETAIN and PERSISTENT variables) and waits until it is in Stop.
+func (c *Client) ResetPlcOrigin(port uint16) error {
+	deviceState := plcResetOriginDeviceState
+	return c.controlPlc("ResetPlcOrigin", port, types.ADSStateReset, types.ADSStateStop, &deviceState)
+}
+
+// controlPlc writes adsState to the runtime on port and waits up to
+// ClientSettings.PlcControlTimeout for it to reach target. Without an explicit
+// deviceState the current device state is kept.
+func (c *Client) controlPlc(operationName string, port uint16, adsState, target types.ADSState, deviceState *uint16) error {
+	c.logger.Info(operationName+": Controlling PLC runtime", "port", port, "adsState", adsState.String())
+	if port == types.ADSReservedPortSystemService {
+		return fmt.Errorf("%s: port %d is the system service, use SetTcSystemToRun/SetTcSystemToConfig", operationName, port)
+	}
+	if _, _, err := c.activeConn(); err != nil {
+		return fmt.Errorf("%s: Use Connect() to connect to the target first: %w", operationName, err)
+	}
+
+	if deviceState == nil {
+		// Reading device state first as we don't want to change it
+		state, err := c.ReadPlcState(port)
+		if err != nil {
+			c.logger.Error(operationName+": Failed to read current PLC runtime state", "port", port, "error", err)
+			return fmt.Errorf("%s: failed to read current PLC runtime state: %w", operationName, err)
+		}
+		deviceState = &state.DeviceState
+	}
+
+	if err := c.WriteControl(adsState, *deviceState, port); err != nil {
+		c.logger.Error(operationName+": Failed to send WriteControl command", "port", port, "error", err)
+		return fmt.Errorf("%s: %w", operationName, err)
+	}
+
+	if err := c.waitForPlcState(operationName, port, target); err != nil {
+		return err
+	}
+	c.logger.Info(operationName+": PLC runtime reached target state", "port", port, "state", target.String())
+	return nil
+}
+
+// waitForPlcState reads the runtime state of port until it is target or
+// ClientSettings.PlcControlTimeout expired. Read errors while the runtime is
+// switching are retried.
+func (c *Client) waitForPlcState(operationName string, port uint16, target types.ADSState) error {
+	deadline := time.Now().Add(c.settings.PlcControlTimeout)
+	var last types.ADSState
+	var lastErr error
+	for {
+		state, err := c.ReadPlcState(port)
+		if err == nil && state.AdsState == target {
+			return nil
+		}
+		if err == nil {
+			last = state.AdsState
+		}
+		lastErr = err
+
+		if time.Now().After(deadline) {
+			if lastErr != nil {
+				return fmt.Errorf("%s: %w - PLC runtime on port %d did not reach %s within %s: %w",
+					operationName, ErrPlcControlTimeout, port, target.String(), c.settings.PlcControlTimeout, lastErr)
+			}
+			return fmt.Errorf("%s: %w - PLC runtime on port %d is in %s mode (expected %s) after %s",
+				operationName, ErrPlcControlTimeout, port, last.String(), target.String(), c.settings.PlcControlTimeout)
+		}
+		time.Sleep(plcControlPollInterval)
+	}
+}
@@ -0,0 +1,88 @@
+package ads
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPlcControl verifies that the runtime control commands write the right
+// state to the runtime port and wait for the target state.
+func TestPlcControl(t *testing.T) {
+	var plcState, lastControl, lastDeviceState atomic.Uint32
+	var frozen atomic.Bool
+	plcState.Store(uint32(types.ADSStateRun))
+	router := newFakeRouter(t, func(cmd types.ADSCommand, port uint16, data []byte) []byte {
+		if port != 851 {
+			return defaultFakeHandler(cmd, port, data)
+		}
+		switch cmd {
+		case types.ADSCommandWriteControl:
+			control := types.ADSState(binary.LittleEndian.Uint16(data[0:2]))
+			lastControl.Store(uint32(control))
+			lastDeviceState.Store(uint32(binary.LittleEndian.Uint16(data[2:4])))
+			if !frozen.Load() {
+				if control == types.ADSStateReset {
+					control = types.ADSStateStop
+				}
+				plcState.Store(uint32(control))
+			}
+			return make([]byte, 4)
+		case types.ADSCommandReadState:
+			resp := make([]byte, 8)
+			binary.LittleEndian.PutUint16(resp[4:6], uint16(plcState.Load()))
+			binary.LittleEndian.PutUint16(resp[6:8], uint16(lastDeviceState.Load()))
+			return resp
+		}
+		return defaultFakeHandler(cmd, port, data)
+	})
+	settings := router.settings()
+	settings.PlcControlTimeout = 200 * time.Millisecond
+	c := NewClient(settings, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer func() { _ = c.Disconnect() }()
+
+	if err := c.StopPlc(851); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, uint32(types.ADSStateStop), lastControl.Load())
+	assert.Equal(t, types.ADSStateStop, c.GetPlcState(851).AdsState)
+
+	if err := c.StartPlc(851); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, uint32(types.ADSStateRun), lastControl.Load())
+
+	if err := c.ResetPlcCold(851); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, uint32(types.ADSStateReset), lastControl.Load())
+	assert.Equal(t, uint32(0), lastDeviceState.Load())
+
+	if err := c.ResetPlcOrigin(851); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, uint32(plcResetOriginDeviceState), lastDeviceState.Load())
+
+	// The runtime now reports the device state of the reset origin
+	if err := c.ResetPlcCold(851); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, uint32(plcResetColdDeviceState), lastDeviceState.Load(), "reset cold after reset origin")
+
+	// The runtime ignores the command
+	frozen.Store(true)
+	err := c.StartPlc(851)
+	assert.True(t, errors.Is(err, ErrPlcControlTimeout), "got %v", err)
+
+	assert.Error(t, c.StartPlc(types.ADSReservedPortSystemService), "system service is rejected")
+}
@@ -0,0 +1,209 @@
+package ads
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	adsstateinfo "github.com/jarmocluyse/ads-go/pkg/ads/ads-stateinfo"
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+)
+
+// PlcStateGate decides whether ReadValue and WriteValue are allowed
+// depending on the state of the PLC runtime on the target port.
+type PlcStateGate int
+
+const (
+	// PlcStateGateKnown rejects operations when the cached state of the
+	// runtime is known and not Run. Unknown states are allowed.
+	PlcStateGateKnown PlcStateGate = iota
+	// PlcStateGateStrict reads the state of the runtime when it is not cached
+	// yet and rejects operations unless it is Run.
+	PlcStateGateStrict
+	// PlcStateGateOff does not gate on the runtime state. The TwinCAT system
+	// state is still checked.
+	PlcStateGateOff
+)
+
+// String returns the string representation of the gate policy.
+func (g PlcStateGate) String() string {
+	switch g {
+	case PlcStateGateKnown:
+		return "Known"
+	case PlcStateGateStrict:
+		return "Strict"
+	case PlcStateGateOff:
+		return "Off"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// GetPlcState returns the cached state of the PLC runtime on port.
+// Returns nil if the port is not tracked or its state was not read yet.
+//
+// A port is tracked once it is used by ReadValue, WriteValue, a subscription
+// or ReadPlcState. The state poller (and StateNotifications) keep the state
+// of tracked ports up to date and call OnPlcStateChange on changes.
+func (c *Client) GetPlcState(port uint16) *adsstateinfo.SystemState {
+	c.plcStateMutex.Lock()
+	defer c.plcStateMutex.Unlock()
+	return c.plcStates[port]
+}
+
+// trackPlcPort starts tracking the runtime state of port. Ports of the
+// system service are ignored.
+func (c *Client) trackPlcPort(port uint16) {
+	if port == types.ADSReservedPortSystemService {
+		return
+	}
+	c.plcStateMutex.Lock()
+	if _, ok := c.plcStates[port]; ok {
+		c.plcStateMutex.Unlock()
+		return
+	}
+	c.plcStates[port] = nil
+	c.plcStateMutex.Unlock()
+
+	c.logger.Debug("trackPlcPort: Tracking PLC runtime state", "port", port)
+	c.statePollerMutex.Lock()
+	monitoring := c.stateSubscription != nil
+	c.statePollerMutex.Unlock()
+	if monitoring {
+		c.startPlcStateNotification(port)
+	}
+}
+
+// trackedPlcPorts returns the tracked runtime ports.
+func (c *Client) trackedPlcPorts() []uint16 {
+	c.plcStateMutex.Lock()
+	defer c.plcStateMutex.Unlock()
+	ports := make([]uint16, 0, len(c.plcStates))
+	for port := range c.plcStates {
+		ports = append(ports, port)
+	}
+	return ports
+}
+
+// applyPlcState caches the state of the runtime on port and fires
+// OnPlcStateChange if the ADS state changed.
+func (c *Client) applyPlcState(port uint16, newState *adsstateinfo.SystemState, source string) {
+	c.plcStateMutex.Lock()
+	oldState := c.plcStates[port]
+	c.plcStates[port] = newState
+	c.plcStateMutex.Unlock()
+
+	if oldState != nil && oldState.AdsState == newState.AdsState {
+		return
+	}
+	if oldState == nil {
+		c.logger.Info(source+": Initial PLC runtime state read", "port", port, "state", newState.AdsState.String())
+	} else {
+		c.logger.Info(source+": PLC runtime state changed", "port", port,
+			"from", oldState.AdsState.String(),
+			"to", newState.AdsState.String())
+	}
+
+	if c.settings.OnPlcStateChange == nil {
+		return
+	}
+	go c.invokeHook("OnPlcStateChange", func() {
+		c.settings.OnPlcStateChange(c, port, newState, oldState)
+	})
+}
+
+// clearPlcStates forgets the cached runtime states. The ports stay tracked.
+func (c *Client) clearPlcStates() {
+	c.plcStateMutex.Lock()
+	defer c.plcStateMutex.Unlock()
+	for port := range c.plcStates {
+		c.plcStates[port] = nil
+	}
+}
+
+// checkPlcStates reads the state of every tracked runtime port.
+// Called by the state poller while the system is in Run.
+func (c *Client) checkPlcStates() {
+	for _, port := range c.trackedPlcPorts() {
+		state, err := c.readState("checkPlcStates", port)
+		if err != nil {
+			c.logger.Debug("checkPlcStates: Failed to read PLC runtime state", "port", port, "error", err)
+			continue
+		}
+		c.applyPlcState(port, state, "checkPlcStates")
+	}
+}
+
+// checkPlcStateForOperation applies ClientSettings.PlcStateGate to an
+// operation on port.
+func (c *Client) checkPlcStateForOperation(operationName string, port uint16) error {
+	c.trackPlcPort(port)
+	if c.settings.PlcStateGate == PlcStateGateOff || port == types.ADSReservedPortSystemService {
+		return nil
+	}
+
+	state := c.GetPlcState(port)
+	if state == nil && c.settings.PlcStateGate == PlcStateGateStrict {
+		var err error
+		if state, err = c.ReadPlcState(port); err != nil {
+			return fmt.Errorf("%s: failed to read PLC runtime state of port %d: %w", operationName, port, err)
+		}
+	}
+	if state == nil {
+		c.logger.Debug(operationName+": PLC runtime state unknown, allowing operation", "port", port)
+		return nil
+	}
+
+	if state.AdsState != types.ADSStateRun {
+		return fmt.Errorf("%s: %w - PLC runtime on port %d is in %s mode (expected Run)",
+			operationName, ErrPlcNotRunning, port, state.AdsState.String())
+	}
+	return nil
+}
+
+// startPlcStateNotification subscribes to the device state of the runtime on
+// port (StateNotifications).
+func (c *Client) startPlcStateNotification(port uint16) {
+	sub, err := c.addSubscription(subscriptionSpec{
+		port:        port,
+		indexGroup:  uint32(types.ADSReservedIndexGroupDeviceData),
+		indexOffset: 0,
+		size:        4,
+		callback: func(data SubscriptionData) {
+			if state, ok := parseStateNotification(data); ok {
+				c.applyPlcState(port, state, "handleStateNotification")
+			}
+		},
+		settings: SubscriptionSettings{
+			CycleTime:        c.settings.StateNotificationCycleTime,
+			TransmissionMode: types.ADSTransModeOnChange,
+		},
+		isRaw:    true,
+		internal: true,
+	})
+	if err != nil {
+		c.logger.Warn("startPlcStateNotification: Device state notification not available, using polling only", "port", port, "error", err)
+		return
+	}
+
+	c.statePollerMutex.Lock()
+	if old := c.plcStateSubscriptions[port]; old != nil {
+		// Raced with another caller, keep the first one
+		c.statePollerMutex.Unlock()
+		_ = c.unsubscribe(sub, c.send)
+		return
+	}
+	c.plcStateSubscriptions[port] = sub
+	c.statePollerMutex.Unlock()
+}
+
+// parseStateNotification decodes a device state notification:
+// [0..1] ADS state, [2..3] device state.
+func parseStateNotification(data SubscriptionData) (*adsstateinfo.SystemState, bool) {
+	if len(data.RawValue) < 4 {
+		return nil, false
+	}
+	return &adsstateinfo.SystemState{
+		AdsState:    types.ADSState(binary.LittleEndian.Uint16(data.RawValue[0:2])),
+		DeviceState: binary.LittleEndian.Uint16(data.RawValue[2:4]),
+	}, true
+}
@@ -0,0 +1,118 @@
+package ads
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	adsstateinfo "github.com/jarmocluyse/ads-go/pkg/ads/ads-stateinfo"
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// connectPlcStateTestClient connects a client to a fake router that reports
+// plcState for every runtime port and Run for the system service.
+func connectPlcStateTestClient(t *testing.T, plcState *atomic.Uint32, settings func(*ClientSettings)) *Client {
+	router := newFakeRouter(t, func(cmd types.ADSCommand, port uint16, data []byte) []byte {
+		if cmd == types.ADSCommandReadState && port != uint16(types.ADSReservedPortSystemService) {
+			resp := make([]byte, 8)
+			binary.LittleEndian.PutUint16(resp[4:6], uint16(plcState.Load()))
+			return resp
+		}
+		return defaultFakeHandler(cmd, port, data)
+	})
+	clientSettings := router.settings()
+	if settings != nil {
+		settings(&clientSettings)
+	}
+	c := NewClient(clientSettings, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	t.Cleanup(func() { _ = c.Disconnect() })
+	return c
+}
+
+// TestPlcStateTracking verifies that used runtime ports are tracked and that
+// OnPlcStateChange fires on changes only.
+func TestPlcStateTracking(t *testing.T) {
+	var plcState atomic.Uint32
+	plcState.Store(uint32(types.ADSStateRun))
+	type change struct {
+		port     uint16
+		newState types.ADSState
+		oldState *adsstateinfo.SystemState
+	}
+	changes := make(chan change, 4)
+	c := connectPlcStateTestClient(t, &plcState, func(s *ClientSettings) {
+		s.OnPlcStateChange = func(_ *Client, port uint16, newState, oldState *adsstateinfo.SystemState) {
+			changes <- change{port, newState.AdsState, oldState}
+		}
+	})
+	next := func() change {
+		select {
+		case got := <-changes:
+			return got
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for OnPlcStateChange")
+		}
+		return change{}
+	}
+
+	assert.Nil(t, c.GetPlcState(851), "port not used yet")
+	if _, err := c.ReadPlcState(851); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	first := next()
+	assert.Equal(t, uint16(851), first.port)
+	assert.Equal(t, types.ADSStateRun, first.newState)
+	assert.Nil(t, first.oldState, "no previous state on the first read")
+
+	c.checkPlcStates()
+	select {
+	case got := <-changes:
+		t.Fatalf("Expected no state change, got %v", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	plcState.Store(uint32(types.ADSStateStop))
+	c.checkPlcStates()
+	stopped := next()
+	assert.Equal(t, types.ADSStateStop, stopped.newState)
+	assert.Equal(t, types.ADSStateRun, stopped.oldState.AdsState)
+	assert.Equal(t, types.ADSStateStop, c.Debug().PlcStates[851].AdsState)
+}
+
+func TestPlcStateGate(t *testing.T) {
+	var plcState atomic.Uint32
+	plcState.Store(uint32(types.ADSStateStop))
+
+	t.Run("known", func(t *testing.T) {
+		c := connectPlcStateTestClient(t, &plcState, nil)
+		assert.NoError(t, c.checkStateForOperation("ReadValue", 851), "unknown state is allowed")
+		if _, err := c.ReadPlcState(851); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		err := c.checkStateForOperation("ReadValue", 851)
+		assert.True(t, errors.Is(err, ErrPlcNotRunning), "got %v", err)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		c := connectPlcStateTestClient(t, &plcState, func(s *ClientSettings) { s.PlcStateGate = PlcStateGateStrict })
+		err := c.checkStateForOperation("WriteValue", 852)
+		assert.True(t, errors.Is(err, ErrPlcNotRunning), "got %v", err)
+		assert.NotNil(t, c.GetPlcState(852), "state read by the gate")
+	})
+
+	t.Run("off", func(t *testing.T) {
+		c := connectPlcStateTestClient(t, &plcState, func(s *ClientSettings) { s.PlcStateGate = PlcStateGateOff })
+		if _, err := c.ReadPlcState(851); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		assert.NoError(t, c.checkStateForOperation("ReadValue", 851))
+	})
+}
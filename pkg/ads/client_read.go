@@ -7,32 +7,36 @@ import (
 	"github.com/jarmocluyse/ads-go/pkg/ads/types"
 )
 
+// ReadValue reads the variable path and converts it to a Go value of its data
+// type. References and dereferenced pointers ("GVL.pAxis^") are read through a
+// variable handle that is created and released on every call, which costs
+// three round trips instead of one.
 func (c *Client) ReadValue(port uint16, path string) (any, error) {
 	c.logger.Debug("ReadValue: Reading value", "path", path)
 
 	// Check if system is in Run mode before reading
-	if err := c.checkStateForOperation("ReadValue"); err != nil {
+	if err := c.checkStateForOperation("ReadValue", port); err != nil {
 		return nil, err
 	}
 
-	symbol, err := c.GetSymbol(port, path)
+	target, err := c.resolveVariable(port, path)
 	if err != nil {
-		return nil, fmt.Errorf("ReadValue: failed to get symbol: %w", err)
+		return nil, fmt.Errorf("ReadValue: %w", err)
 	}
-	c.logger.Debug("symbol received", "symbol", symbol)
+	c.logger.Debug("symbol received", "symbol", target.symbol)
 
-	dataType, err := c.GetDataType(symbol.Type, port)
-	if err != nil {
-		return nil, fmt.Errorf("ReadValue: failed to get data type: %w", err)
+	var data []byte
+	if target.dereference {
+		data, err = c.readByHandle(port, path, target.dataType.Size)
+	} else {
+		data, err = c.ReadRaw(port, target.symbol.IndexGroup, target.symbol.IndexOffset, target.symbol.Size)
 	}
-
-	data, err := c.ReadRaw(port, symbol.IndexGroup, symbol.IndexOffset, symbol.Size)
 	if err != nil {
 		return nil, fmt.Errorf("ReadValue: failed to read raw data: %w", err)
 	}
-	return c.convertBufferToValue(data, dataType)
+	return c.convertBufferToValue(data, target.dataType)
 }
 
 func (c *Client) convertBufferToValue(data []byte, dataType types.AdsDataType, isArrayItem ...bool) (any, error) {
-	return adsserializer.Deserialize(data, dataType, isArrayItem...)
+	return adsserializer.DeserializeWithOptions(data, dataType, c.serializerOptions(), isArrayItem...)
 }
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	adsstateinfo "github.com/jarmocluyse/ads-go/pkg/ads/ads-stateinfo"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -15,10 +16,17 @@ import (
 // It bypasses NewClient/Connect so we can control conn directly.
 func newTestClient(settings ClientSettings) *Client {
 	return &Client{
-		settings:      settings,
-		requests:      make(map[uint32]chan Response),
-		subscriptions: make(map[uint32]*ActiveSubscription),
-		logger:        slog.New(slog.NewTextHandler(io.Discard, nil)),
+		settings:              settings,
+		requests:              make(map[uint32]*pendingRequest),
+		subscriptions:         make(map[uint32]*ActiveSubscription),
+		clientSubscriptions:   make(map[uint32]*ActiveSubscription),
+		multiplexGroups:       make(map[string]*multiplexGroup),
+		pollGroups:            make(map[string]*pollGroup),
+		unknownHandles:        make(map[uint16]int),
+		symbolVersions:        make(map[uint16]uint8),
+		plcStates:             make(map[uint16]*adsstateinfo.SystemState),
+		plcStateSubscriptions: make(map[uint16]*ActiveSubscription),
+		logger:                slog.New(slog.NewTextHandler(io.Discard, nil)),
 	}
 }
 
@@ -0,0 +1,410 @@
+package ads
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	adsstateinfo "github.com/jarmocluyse/ads-go/pkg/ads/ads-stateinfo"
+)
+
+// RedundantClientSettings holds the settings of a RedundantClient.
+type RedundantClientSettings struct {
+	// ControllerA and ControllerB are the client settings of the two
+	// controllers of the redundancy pair. Their order does not matter, the
+	// active controller follows from the system service state flags.
+	ControllerA ClientSettings
+	ControllerB ClientSettings
+
+	// CheckInterval is how often the extended state of both controllers is
+	// read to find the active one (500ms assumed if empty). A controller that
+	// is not connected is reconnected at the same interval.
+	CheckInterval time.Duration
+
+	// OnRedundancySwitch is called after the active controller changed and the
+	// subscriptions were moved to it (asynchronous). It is not called for the
+	// first choice made by Connect.
+	OnRedundancySwitch func(rc *RedundantClient, active *Client, previous *Client)
+}
+
+// RedundantClient connects to both controllers of a TwinCAT controller
+// redundancy pair. Reads and writes go to the controller that reports itself
+// active (ADSSystemServiceStateFlagRedundancyActive), subscriptions are moved
+// to the other controller on a switchover.
+//
+// If neither controller reports itself active, e.g. on systems without
+// redundancy, the current controller is kept while it is reachable; otherwise
+// the primary or any reachable controller is used.
+type RedundantClient struct {
+	settings      RedundantClientSettings
+	logger        *slog.Logger
+	controllers   [2]*Client
+	active        int                                 // index of the active controller (-1 = none, protected by mutex)
+	mutex         sync.RWMutex                        // protects active
+	subscriptions map[*RedundantSubscription]struct{} // subscriptions to keep on the active controller (protected by switchMutex)
+	switchMutex   sync.Mutex                          // serializes switchovers with Subscribe and Unsubscribe
+	done          chan struct{}                       // closed by Disconnect to stop the monitor
+	monitor       sync.WaitGroup                      // monitor goroutine
+}
+
+// RedundantSubscription is a subscription of a RedundantClient. It is
+// re-created on the active controller after every switchover.
+type RedundantSubscription struct {
+	subscribe func(c *Client) (*ActiveSubscription, error)
+	mutex     sync.Mutex          // protects client and sub
+	client    *Client             // controller holding sub (nil while it could not be created)
+	sub       *ActiveSubscription // subscription on client
+}
+
+// Current returns the subscription on the active controller, or nil while it
+// could not be created there (it is retried every CheckInterval).
+func (s *RedundantSubscription) Current() *ActiveSubscription {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.sub
+}
+
+// NewRedundantClient creates a client for a controller redundancy pair.
+func NewRedundantClient(settings RedundantClientSettings, logger *slog.Logger) *RedundantClient {
+	if logger == nil { // silent logger when not added
+		logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	if settings.CheckInterval == 0 {
+		settings.CheckInterval = 500 * time.Millisecond
+	}
+
+	logger.Info("NewRedundantClient: Initializing redundant ADS client.")
+	return &RedundantClient{
+		settings: settings,
+		logger:   logger,
+		controllers: [2]*Client{
+			NewClient(settings.ControllerA, logger.With("controller", "A")),
+			NewClient(settings.ControllerB, logger.With("controller", "B")),
+		},
+		active:        -1,
+		subscriptions: make(map[*RedundantSubscription]struct{}),
+	}
+}
+
+// Connect connects to both controllers and chooses the active one. It fails
+// only if neither controller can be reached; the other one is reconnected in
+// the background.
+func (rc *RedundantClient) Connect() error {
+	rc.switchMutex.Lock()
+	running := rc.done != nil
+	rc.switchMutex.Unlock()
+	if running {
+		return fmt.Errorf("Connect: already connected")
+	}
+
+	errs := make([]error, 0, 2)
+	for i, c := range rc.controllers {
+		if err := c.Connect(); err != nil {
+			rc.logger.Warn("Connect: Failed to connect controller", "controller", controllerName(i), "error", err)
+			errs = append(errs, fmt.Errorf("controller %s: %w", controllerName(i), err))
+		}
+	}
+	if len(errs) == len(rc.controllers) {
+		return fmt.Errorf("Connect: %w", errors.Join(errs...))
+	}
+
+	rc.check(false)
+
+	rc.switchMutex.Lock()
+	rc.done = make(chan struct{})
+	rc.monitor.Add(1)
+	go rc.runMonitor(rc.done)
+	rc.switchMutex.Unlock()
+	return nil
+}
+
+// Disconnect stops the monitoring and disconnects both controllers.
+func (rc *RedundantClient) Disconnect() error {
+	rc.switchMutex.Lock()
+	if rc.done != nil {
+		close(rc.done)
+		rc.done = nil
+	}
+	clear(rc.subscriptions)
+	rc.switchMutex.Unlock()
+	rc.monitor.Wait()
+
+	rc.mutex.Lock()
+	rc.active = -1
+	rc.mutex.Unlock()
+
+	var errs []error
+	for i, c := range rc.controllers {
+		if err := c.Disconnect(); err != nil {
+			errs = append(errs, fmt.Errorf("controller %s: %w", controllerName(i), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Controllers returns the clients of controller A and B.
+func (rc *RedundantClient) Controllers() (*Client, *Client) {
+	return rc.controllers[0], rc.controllers[1]
+}
+
+// Active returns the client of the active controller, or nil if no
+// controller was reachable yet.
+func (rc *RedundantClient) Active() *Client {
+	rc.mutex.RLock()
+	defer rc.mutex.RUnlock()
+	if rc.active < 0 {
+		return nil
+	}
+	return rc.controllers[rc.active]
+}
+
+// activeClient returns the active controller or ErrNoActiveController.
+func (rc *RedundantClient) activeClient(operation string) (*Client, error) {
+	c := rc.Active()
+	if c == nil {
+		return nil, fmt.Errorf("%s: %w", operation, ErrNoActiveController)
+	}
+	return c, nil
+}
+
+// ReadValue reads a variable by path from the active controller.
+func (rc *RedundantClient) ReadValue(port uint16, path string) (any, error) {
+	c, err := rc.activeClient("ReadValue")
+	if err != nil {
+		return nil, err
+	}
+	return c.ReadValue(port, path)
+}
+
+// WriteValue writes a variable by path on the active controller.
+func (rc *RedundantClient) WriteValue(port uint16, path string, value any) error {
+	c, err := rc.activeClient("WriteValue")
+	if err != nil {
+		return err
+	}
+	return c.WriteValue(port, path, value)
+}
+
+// ReadRaw reads raw data from the active controller.
+func (rc *RedundantClient) ReadRaw(port uint16, indexGroup uint32, indexOffset uint32, size uint32) ([]byte, error) {
+	c, err := rc.activeClient("ReadRaw")
+	if err != nil {
+		return nil, err
+	}
+	return c.ReadRaw(port, indexGroup, indexOffset, size)
+}
+
+// WriteRaw writes raw data to the active controller.
+func (rc *RedundantClient) WriteRaw(port uint16, indexGroup uint32, indexOffset uint32, data []byte) error {
+	c, err := rc.activeClient("WriteRaw")
+	if err != nil {
+		return err
+	}
+	return c.WriteRaw(port, indexGroup, indexOffset, data)
+}
+
+// ReadWriteRaw reads and writes raw data on the active controller.
+func (rc *RedundantClient) ReadWriteRaw(port uint16, indexGroup uint32, indexOffset uint32, readLength uint32, writeData []byte) ([]byte, error) {
+	c, err := rc.activeClient("ReadWriteRaw")
+	if err != nil {
+		return nil, err
+	}
+	return c.ReadWriteRaw(port, indexGroup, indexOffset, readLength, writeData)
+}
+
+// SubscribeValue subscribes to a variable by path on the active controller
+// (see Client.SubscribeValue). The subscription moves to the other
+// controller on a switchover; the callback keeps receiving its values.
+func (rc *RedundantClient) SubscribeValue(port uint16, path string, callback SubscriptionCallback, settings SubscriptionSettings) (*RedundantSubscription, error) {
+	return rc.addSubscription("SubscribeValue", func(c *Client) (*ActiveSubscription, error) {
+		return c.SubscribeValue(port, path, callback, settings)
+	})
+}
+
+// SubscribeRaw subscribes to a raw ADS address on the active controller
+// (see Client.SubscribeRaw). The subscription moves to the other controller
+// on a switchover.
+func (rc *RedundantClient) SubscribeRaw(port uint16, indexGroup, indexOffset, size uint32, callback SubscriptionCallback, settings SubscriptionSettings) (*RedundantSubscription, error) {
+	return rc.addSubscription("SubscribeRaw", func(c *Client) (*ActiveSubscription, error) {
+		return c.SubscribeRaw(port, indexGroup, indexOffset, size, callback, settings)
+	})
+}
+
+// addSubscription creates a subscription on the active controller and keeps
+// it for switchovers.
+func (rc *RedundantClient) addSubscription(operation string, subscribe func(c *Client) (*ActiveSubscription, error)) (*RedundantSubscription, error) {
+	rc.switchMutex.Lock()
+	defer rc.switchMutex.Unlock()
+
+	c, err := rc.activeClient(operation)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := subscribe(c)
+	if err != nil {
+		return nil, err
+	}
+	rs := &RedundantSubscription{subscribe: subscribe, client: c, sub: sub}
+	rc.subscriptions[rs] = struct{}{}
+	return rs, nil
+}
+
+// Unsubscribe removes a subscription from the active controller.
+func (rc *RedundantClient) Unsubscribe(rs *RedundantSubscription) error {
+	rc.switchMutex.Lock()
+	defer rc.switchMutex.Unlock()
+
+	delete(rc.subscriptions, rs)
+	rs.mutex.Lock()
+	c, sub := rs.client, rs.sub
+	rs.client, rs.sub = nil, nil
+	rs.mutex.Unlock()
+	if sub == nil {
+		return nil
+	}
+	return c.Unsubscribe(sub)
+}
+
+// runMonitor checks the controllers every CheckInterval until done is closed.
+func (rc *RedundantClient) runMonitor(done chan struct{}) {
+	defer rc.monitor.Done()
+	ticker := time.NewTicker(rc.settings.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			rc.check(true)
+		}
+	}
+}
+
+// check reads the extended state of both controllers, switches to the active
+// one and makes sure every subscription exists on it. Disconnected
+// controllers are reconnected if reconnect is set.
+func (rc *RedundantClient) check(reconnect bool) {
+	var states [2]*adsstateinfo.ExtendedSystemState
+	for i, c := range rc.controllers {
+		if c.ConnectionState() == ConnectionStateDisconnected {
+			if !reconnect {
+				continue
+			}
+			if err := c.Connect(); err != nil {
+				rc.logger.Debug("check: Controller still unreachable", "controller", controllerName(i), "error", err)
+				continue
+			}
+		}
+		state, err := c.ReadTcSystemExtendedState()
+		if err != nil {
+			rc.logger.Debug("check: Failed to read extended state", "controller", controllerName(i), "error", err)
+			continue
+		}
+		states[i] = state
+	}
+
+	rc.switchMutex.Lock()
+	defer rc.switchMutex.Unlock()
+
+	rc.mutex.Lock()
+	previous := rc.active
+	active := chooseActiveController(previous, states)
+	if active >= 0 {
+		rc.active = active
+	}
+	rc.mutex.Unlock()
+
+	if active < 0 {
+		return
+	}
+	if active != previous {
+		rc.logger.Info("check: Active controller changed", "active", controllerName(active), "previous", controllerName(previous))
+	}
+	rc.moveSubscriptions(rc.controllers[active])
+
+	if active != previous && previous >= 0 {
+		activeClient, previousClient := rc.controllers[active], rc.controllers[previous]
+		go activeClient.invokeHook("OnRedundancySwitch", func() {
+			rc.settings.OnRedundancySwitch(rc, activeClient, previousClient)
+		})
+	}
+}
+
+// moveSubscriptions creates every subscription that is not on c yet, or that
+// died on c (e.g. invalidated), on c and removes it from the other controller.
+// Must be called with switchMutex held.
+func (rc *RedundantClient) moveSubscriptions(c *Client) {
+	for rs := range rc.subscriptions {
+		rs.mutex.Lock()
+		oldClient, oldSub := rs.client, rs.sub
+		rs.mutex.Unlock()
+		if oldClient == c && oldSub != nil && oldSub.State() != SubscriptionStateDead {
+			continue
+		}
+
+		// Subscribe first, a duplicate sample is better than a gap
+		sub, err := rs.subscribe(c)
+		if err != nil {
+			rc.logger.Warn("check: Failed to move subscription, retrying", "error", err)
+			continue
+		}
+		rs.mutex.Lock()
+		rs.client, rs.sub = c, sub
+		rs.mutex.Unlock()
+
+		if oldSub != nil {
+			if err := oldClient.Unsubscribe(oldSub); err != nil {
+				rc.logger.Debug("check: Failed to remove subscription from previous controller", "error", err)
+			}
+		}
+	}
+}
+
+// chooseActiveController returns the index of the controller to use given
+// the extended state of both (nil = unreachable), or -1 if none is reachable.
+func chooseActiveController(current int, states [2]*adsstateinfo.ExtendedSystemState) int {
+	candidates := make([]int, 0, 2)
+	for i, state := range states {
+		if state != nil && state.IsRedundancyActive() {
+			candidates = append(candidates, i)
+		}
+	}
+	switch {
+	case len(candidates) == 1:
+		return candidates[0]
+	case len(candidates) == 2 && current >= 0:
+		return current // both claim to be active, do not flap
+	}
+
+	// No controller reports itself active (no redundancy, or in transition)
+	if current >= 0 && states[current] != nil {
+		return current
+	}
+	for i, state := range states {
+		if state != nil && state.IsRedundancyPrimary() {
+			return i
+		}
+	}
+	for i, state := range states {
+		if state != nil {
+			return i
+		}
+	}
+	return -1
+}
+
+// controllerName returns the name of the controller with index i.
+func controllerName(i int) string {
+	switch i {
+	case 0:
+		return "A"
+	case 1:
+		return "B"
+	default:
+		return "none"
+	}
+}
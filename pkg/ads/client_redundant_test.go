@@ -0,0 +1,180 @@
+package ads
+
+import (
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	adsstateinfo "github.com/jarmocluyse/ads-go/pkg/ads/ads-stateinfo"
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// redundantController is a fake controller of a redundancy pair.
+type redundantController struct {
+	router  *fakeRouter
+	flags   atomic.Uint32 // system service state flags
+	reads   atomic.Int32  // reads of index group 0x4020
+	added   atomic.Int32  // AddNotification requests
+	deleted atomic.Int32  // DeleteNotification requests
+}
+
+func newRedundantController(t *testing.T, flags types.ADSSystemServiceStateFlags) *redundantController {
+	rc := &redundantController{}
+	rc.flags.Store(uint32(flags))
+	rc.router = newFakeRouter(t, func(cmd types.ADSCommand, port uint16, data []byte) []byte {
+		switch cmd {
+		case types.ADSCommandRead:
+			switch binary.LittleEndian.Uint32(data[0:4]) {
+			case 240: // extended state
+				resp := make([]byte, 8+16)
+				binary.LittleEndian.PutUint32(resp[4:8], 16)
+				binary.LittleEndian.PutUint16(resp[8:10], uint16(types.ADSStateRun))
+				binary.LittleEndian.PutUint16(resp[8+12:], uint16(rc.flags.Load()))
+				return resp
+			case 0x4020:
+				rc.reads.Add(1)
+				resp := make([]byte, 12)
+				binary.LittleEndian.PutUint32(resp[4:8], 4)
+				return resp
+			}
+		case types.ADSCommandAddNotification:
+			resp := make([]byte, 8)
+			binary.LittleEndian.PutUint32(resp[4:8], uint32(100+rc.added.Add(1)))
+			return resp
+		case types.ADSCommandDeleteNotification:
+			rc.deleted.Add(1)
+		}
+		return defaultFakeHandler(cmd, port, data)
+	})
+	return rc
+}
+
+func TestRedundantClientSwitchover(t *testing.T) {
+	const (
+		system  = types.ADSSystemServiceStateFlagRedundancySystem
+		primary = types.ADSSystemServiceStateFlagRedundancyPrimary
+		active  = types.ADSSystemServiceStateFlagRedundancyActive
+	)
+	a := newRedundantController(t, system|primary|active)
+	b := newRedundantController(t, system)
+
+	switches := make(chan [2]*Client, 1)
+	rc := NewRedundantClient(RedundantClientSettings{
+		ControllerA:   a.router.settings(),
+		ControllerB:   b.router.settings(),
+		CheckInterval: 10 * time.Millisecond,
+		OnRedundancySwitch: func(rc *RedundantClient, active *Client, previous *Client) {
+			switches <- [2]*Client{active, previous}
+		},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := rc.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer func() { _ = rc.Disconnect() }()
+	clientA, clientB := rc.Controllers()
+	assert.Same(t, clientA, rc.Active())
+
+	sub, err := rc.SubscribeRaw(851, 0x4020, 0, 4, func(SubscriptionData) {}, SubscriptionSettings{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, int32(1), a.added.Load())
+	if _, err := rc.ReadRaw(851, 0x4020, 0, 4); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, int32(1), a.reads.Load())
+
+	// B takes over
+	a.flags.Store(uint32(system | primary))
+	b.flags.Store(uint32(system | active))
+	select {
+	case sw := <-switches:
+		assert.Same(t, clientB, sw[0])
+		assert.Same(t, clientA, sw[1])
+	case <-time.After(time.Second):
+		t.Fatalf("Expected OnRedundancySwitch")
+	}
+	assert.Same(t, clientB, rc.Active())
+	assert.Equal(t, int32(1), b.added.Load(), "subscription moved to B")
+	assert.Equal(t, int32(1), a.deleted.Load(), "subscription removed from A")
+	assert.Equal(t, uint32(101), sub.Current().Handle)
+
+	if _, err := rc.ReadRaw(851, 0x4020, 0, 4); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, int32(1), a.reads.Load())
+	assert.Equal(t, int32(1), b.reads.Load())
+
+	if err := rc.Unsubscribe(sub); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, int32(1), b.deleted.Load())
+	assert.Nil(t, sub.Current())
+}
+
+func TestRedundantClientResubscribe(t *testing.T) {
+	const active = types.ADSSystemServiceStateFlagRedundancySystem | types.ADSSystemServiceStateFlagRedundancyActive
+	a := newRedundantController(t, active)
+	b := newRedundantController(t, types.ADSSystemServiceStateFlagRedundancySystem)
+	rc := NewRedundantClient(RedundantClientSettings{
+		ControllerA:   a.router.settings(),
+		ControllerB:   b.router.settings(),
+		CheckInterval: 10 * time.Millisecond,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := rc.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer func() { _ = rc.Disconnect() }()
+	clientA, _ := rc.Controllers()
+
+	sub, err := rc.SubscribeRaw(851, 0x4020, 0, 4, func(SubscriptionData) {}, SubscriptionSettings{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	first := sub.Current()
+
+	// The subscription dies on the active controller
+	if err := clientA.Unsubscribe(first); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, SubscriptionStateDead, first.State())
+
+	waitFor(t, time.Second, func() bool { return a.added.Load() == 2 }, "subscription re-created on A")
+	waitFor(t, time.Second, func() bool { return sub.Current() != first }, "subscription replaced")
+	assert.Same(t, clientA, rc.Active())
+	assert.NotEqual(t, SubscriptionStateDead, sub.Current().State())
+	assert.Equal(t, int32(0), b.added.Load())
+}
+
+func TestChooseActiveController(t *testing.T) {
+	state := func(flags types.ADSSystemServiceStateFlags) *adsstateinfo.ExtendedSystemState {
+		return &adsstateinfo.ExtendedSystemState{Flags: uint16(flags)}
+	}
+	active := types.ADSSystemServiceStateFlagRedundancySystem | types.ADSSystemServiceStateFlagRedundancyActive
+	standby := types.ADSSystemServiceStateFlagRedundancySystem
+	primary := types.ADSSystemServiceStateFlagRedundancySystem | types.ADSSystemServiceStateFlagRedundancyPrimary
+
+	tests := []struct {
+		name     string
+		current  int
+		states   [2]*adsstateinfo.ExtendedSystemState
+		expected int
+	}{
+		{"active flag wins", 0, [2]*adsstateinfo.ExtendedSystemState{state(standby), state(active)}, 1},
+		{"both active keeps current", 1, [2]*adsstateinfo.ExtendedSystemState{state(active), state(active)}, 1},
+		{"no redundancy keeps current", 1, [2]*adsstateinfo.ExtendedSystemState{state(0), state(0)}, 1},
+		{"current unreachable", 0, [2]*adsstateinfo.ExtendedSystemState{nil, state(0)}, 1},
+		{"primary preferred initially", -1, [2]*adsstateinfo.ExtendedSystemState{state(standby), state(primary)}, 1},
+		{"first reachable initially", -1, [2]*adsstateinfo.ExtendedSystemState{state(0), state(0)}, 0},
+		{"none reachable", 0, [2]*adsstateinfo.ExtendedSystemState{nil, nil}, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, chooseActiveController(tt.current, tt.states))
+		})
+	}
+}
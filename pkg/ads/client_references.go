@@ -0,0 +1,191 @@
+package ads
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	adssymbol "github.com/jarmocluyse/ads-go/pkg/ads/ads-symbol"
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+	"github.com/jarmocluyse/ads-go/pkg/ads/utils"
+)
+
+// variable is what ReadValue and WriteValue need to know about a path.
+type variable struct {
+	symbol      *adssymbol.AdsSymbol // symbol of the path (nil if the path dereferences a pointer)
+	dataType    types.AdsDataType    // type of the value, the referenced type for references
+	dereference bool                 // the value is accessed through a variable handle
+}
+
+// resolveVariable finds the symbol and data type of path. References
+// (REFERENCE TO) and dereferenced pointers ("GVL.pAxis^", "GVL.pAxis^.nState")
+// resolve to the referenced type and are accessed through a variable handle,
+// because only the PLC can follow the address. A pointer without ^ is the
+// address itself.
+func (c *Client) resolveVariable(port uint16, path string) (variable, error) {
+	var result variable
+	var typeName string
+	if strings.Contains(path, "^") {
+		name, err := c.dereferencedTypeName(port, path)
+		if err != nil {
+			return variable{}, err
+		}
+		typeName = name
+		result.dereference = true
+	} else {
+		symbol, err := c.GetSymbol(port, path)
+		if err != nil {
+			return variable{}, fmt.Errorf("failed to get symbol: %w", err)
+		}
+		result.symbol = symbol
+		typeName = symbol.Type
+	}
+
+	dataType, err := c.GetDataType(typeName, port)
+	if err != nil {
+		return variable{}, fmt.Errorf("failed to get data type: %w", err)
+	}
+	if dataType.Flags&types.ADSDataTypeFlagReferenceTo != 0 ||
+		(result.symbol != nil && result.symbol.Flags&types.ADSSymbolFlagReferenceTo != 0) {
+		// The built type is named after the reference, the declaration knows
+		// what it refers to
+		declaration, err := c.getDataTypeDeclaration(typeName, port)
+		if err != nil {
+			return variable{}, fmt.Errorf("failed to get data type: %w", err)
+		}
+		typeName = referencedTypeName(declaration)
+		if dataType, err = c.GetDataType(typeName, port); err != nil {
+			return variable{}, fmt.Errorf("failed to get data type: %w", err)
+		}
+		result.dereference = true
+	}
+	result.dataType = dataType
+
+	if result.dereference {
+		c.logger.Debug("resolveVariable: Path is dereferenced", "path", path, "type", typeName)
+	}
+	return result, nil
+}
+
+// dereferencedTypeName returns the type name of a path that contains the
+// dereference operator. The part before the last ^ must be a pointer or
+// reference; the members after it are looked up in the referenced type.
+func (c *Client) dereferencedTypeName(port uint16, path string) (string, error) {
+	i := strings.LastIndex(path, "^")
+	pointerPath, members := path[:i], path[i+1:]
+
+	var pointerType string
+	if strings.Contains(pointerPath, "^") {
+		name, err := c.dereferencedTypeName(port, pointerPath)
+		if err != nil {
+			return "", err
+		}
+		pointerType = name
+	} else {
+		symbol, err := c.GetSymbol(port, pointerPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to get symbol: %w", err)
+		}
+		pointerType = symbol.Type
+	}
+
+	declaration, err := c.getDataTypeDeclaration(pointerType, port)
+	if err != nil {
+		return "", fmt.Errorf("failed to get data type: %w", err)
+	}
+	if declaration.Flags&(types.ADSDataTypeFlagPlcPointerType|types.ADSDataTypeFlagReferenceTo) == 0 {
+		return "", fmt.Errorf("%s is not a pointer or reference (%s)", pointerPath, pointerType)
+	}
+	typeName := referencedTypeName(declaration)
+
+	// Walk the members that follow the ^
+	if members == "" {
+		return typeName, nil
+	}
+	if !strings.HasPrefix(members, ".") || strings.ContainsAny(members, "[]") {
+		return "", fmt.Errorf("unsupported path after dereference: %q", members)
+	}
+	for _, member := range strings.Split(members[1:], ".") {
+		parent, err := c.getDataTypeDeclaration(typeName, port)
+		if err != nil {
+			return "", fmt.Errorf("failed to get data type: %w", err)
+		}
+		found := false
+		for _, subItem := range parent.SubItems {
+			if strings.EqualFold(subItem.Name, member) {
+				typeName = subItem.Type
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "", fmt.Errorf("%s has no member %s", typeName, member)
+		}
+	}
+	return typeName, nil
+}
+
+// referencedTypeName returns the type a pointer or reference type points to.
+// The declaration carries it as its base type; the name ("POINTER TO
+// ST_Axis") is the fallback.
+func referencedTypeName(declaration types.AdsDataType) string {
+	if declaration.Type != "" {
+		return declaration.Type
+	}
+	for _, prefix := range []string{"POINTER TO ", "REFERENCE TO "} {
+		if len(declaration.Name) > len(prefix) && strings.EqualFold(declaration.Name[:len(prefix)], prefix) {
+			return strings.TrimSpace(declaration.Name[len(prefix):])
+		}
+	}
+	return declaration.Name
+}
+
+// readByHandle reads size bytes of the variable path through a variable
+// handle, which the PLC dereferences.
+func (c *Client) readByHandle(port uint16, path string, size uint32) ([]byte, error) {
+	handle, err := c.createVariableHandle(port, path)
+	if err != nil {
+		return nil, err
+	}
+	defer c.releaseVariableHandle(port, handle)
+	return c.ReadRaw(port, uint32(types.ADSReservedIndexGroupSymbolValueByHandle), handle, size)
+}
+
+// writeByHandle writes the variable path through a variable handle, which the
+// PLC dereferences.
+func (c *Client) writeByHandle(port uint16, path string, data []byte) error {
+	handle, err := c.createVariableHandle(port, path)
+	if err != nil {
+		return err
+	}
+	defer c.releaseVariableHandle(port, handle)
+	return c.WriteRaw(port, uint32(types.ADSReservedIndexGroupSymbolValueByHandle), handle, data)
+}
+
+// createVariableHandle asks the PLC for a handle of the variable path.
+func (c *Client) createVariableHandle(port uint16, path string) (uint32, error) {
+	data, err := c.ReadWriteRaw(
+		port,
+		uint32(types.ADSReservedIndexGroupSymbolHandleByName),
+		0,
+		4,
+		utils.EncodeStringToPlcStringBuffer(path),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create variable handle for %s: %w", path, err)
+	}
+	if len(data) < 4 {
+		return 0, fmt.Errorf("failed to create variable handle for %s: response too short (%d bytes)", path, len(data))
+	}
+	return binary.LittleEndian.Uint32(data[0:4]), nil
+}
+
+// releaseVariableHandle releases a handle of createVariableHandle. Failures
+// are only logged, the PLC drops handles of closed connections anyway.
+func (c *Client) releaseVariableHandle(port uint16, handle uint32) {
+	data := make([]byte, 4)
+	binary.LittleEndian.PutUint32(data, handle)
+	if err := c.WriteRaw(port, uint32(types.ADSReservedIndexGroupSymbolReleaseHandle), 0, data); err != nil {
+		c.logger.Warn("releaseVariableHandle: Failed to release variable handle", "handle", handle, "error", err)
+	}
+}
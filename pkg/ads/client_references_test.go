@@ -0,0 +1,213 @@
+package ads
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// symbolEntry builds the SymbolInfoByNameEx response of a symbol.
+func symbolEntry(name, typeName string, indexGroup, indexOffset, size uint32, flags types.ADSSymbolFlags) []byte {
+	buf := new(bytes.Buffer)
+	_ = binary.Write(buf, binary.LittleEndian, []uint32{0, indexGroup, indexOffset, size, 0})
+	_ = binary.Write(buf, binary.LittleEndian, uint32(flags))
+	_ = binary.Write(buf, binary.LittleEndian, []uint16{uint16(len(name)), uint16(len(typeName)), 0})
+	buf.WriteString(name + "\x00" + typeName + "\x00")
+	return buf.Bytes()
+}
+
+// dataTypeEntry builds a data type entry; subItems are data type entries too.
+func dataTypeEntry(name, typeName string, size, offset uint32, dataType types.ADSDataType, flags types.ADSDataTypeFlags, subItems ...[]byte) []byte {
+	body := new(bytes.Buffer)
+	_ = binary.Write(body, binary.LittleEndian, []uint32{1, 0, 0, size, offset, uint32(dataType), uint32(flags)})
+	_ = binary.Write(body, binary.LittleEndian, []uint16{uint16(len(name)), uint16(len(typeName)), 0, 0, uint16(len(subItems))})
+	body.WriteString(name + "\x00" + typeName + "\x00\x00")
+	for _, subItem := range subItems {
+		body.Write(subItem)
+	}
+	entry := make([]byte, 4, 4+body.Len())
+	binary.LittleEndian.PutUint32(entry, uint32(4+body.Len()))
+	return append(entry, body.Bytes()...)
+}
+
+// referenceRouter is a PLC with an axis struct that GVL.refAxis references and
+// GVL.pAxis points to. Values are only reachable through variable handles.
+type referenceRouter struct {
+	mu       sync.Mutex
+	axis     []byte            // ST_Axis: nState INT, fPos REAL
+	handles  map[uint32]string // open handles
+	released int
+}
+
+func (r *referenceRouter) handle(cmd types.ADSCommand, port uint16, data []byte) []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reply := func(payload []byte) []byte {
+		resp := make([]byte, 8, 8+len(payload))
+		binary.LittleEndian.PutUint32(resp[4:8], uint32(len(payload)))
+		return append(resp, payload...)
+	}
+	fail := func(code uint32) []byte {
+		resp := make([]byte, 8)
+		binary.LittleEndian.PutUint32(resp[0:4], code)
+		return resp
+	}
+
+	indexGroup := types.ADSReservedIndexGroup(binary.LittleEndian.Uint32(data[0:4]))
+	indexOffset := binary.LittleEndian.Uint32(data[4:8])
+	switch cmd {
+	case types.ADSCommandReadWrite:
+		name := strings.TrimRight(string(data[16:]), "\x00")
+		switch indexGroup {
+		case types.ADSReservedIndexGroupSymbolInfoByNameEx:
+			switch name {
+			case "GVL.refAxis":
+				return reply(symbolEntry(name, "REFERENCE TO ST_Axis", 0x4020, 0, 8, types.ADSSymbolFlagReferenceTo))
+			case "GVL.pAxis":
+				return reply(symbolEntry(name, "POINTER TO ST_Axis", 0x4020, 8, 8, 0))
+			case "GVL.nCount":
+				return reply(symbolEntry(name, "INT", 0x4020, 16, 2, 0))
+			}
+		case types.ADSReservedIndexGroupDataDataTypeInfoByNameEx:
+			switch name {
+			case "REFERENCE TO ST_Axis":
+				return reply(dataTypeEntry(name, "ST_Axis", 8, 0, types.ADST_UINT64, types.ADSDataTypeFlagDataType|types.ADSDataTypeFlagReferenceTo))
+			case "POINTER TO ST_Axis":
+				return reply(dataTypeEntry(name, "ST_Axis", 8, 0, types.ADST_UINT64, types.ADSDataTypeFlagDataType|types.ADSDataTypeFlagPlcPointerType))
+			case "ST_Axis":
+				return reply(dataTypeEntry(name, "", 6, 0, types.ADST_BIGTYPE, types.ADSDataTypeFlagDataType,
+					dataTypeEntry("nState", "INT", 2, 0, types.ADST_INT16, types.ADSDataTypeFlagDataType),
+					dataTypeEntry("fPos", "REAL", 4, 2, types.ADST_REAL32, types.ADSDataTypeFlagDataType)))
+			case "INT":
+				return reply(dataTypeEntry(name, "", 2, 0, types.ADST_INT16, types.ADSDataTypeFlagDataType))
+			case "REAL":
+				return reply(dataTypeEntry(name, "", 4, 0, types.ADST_REAL32, types.ADSDataTypeFlagDataType))
+			}
+		case types.ADSReservedIndexGroupSymbolHandleByName:
+			switch name {
+			case "GVL.refAxis", "GVL.pAxis^", "GVL.pAxis^.fPos":
+				handle := uint32(len(r.handles) + 1)
+				r.handles[handle] = name
+				resp := make([]byte, 4)
+				binary.LittleEndian.PutUint32(resp, handle)
+				return reply(resp)
+			}
+		}
+		return fail(1808) // symbol not found
+	case types.ADSCommandRead:
+		if indexGroup == types.ADSReservedIndexGroupSymbolValueByHandle {
+			switch r.handles[indexOffset] {
+			case "GVL.refAxis", "GVL.pAxis^":
+				return reply(r.axis)
+			case "GVL.pAxis^.fPos":
+				return reply(r.axis[2:6])
+			}
+			return fail(1809) // invalid handle
+		}
+		if indexGroup == 0x4020 && indexOffset == 16 {
+			return reply([]byte{0x07, 0x00})
+		}
+	case types.ADSCommandWrite:
+		payload := data[12:]
+		switch indexGroup {
+		case types.ADSReservedIndexGroupSymbolValueByHandle:
+			switch r.handles[indexOffset] {
+			case "GVL.refAxis", "GVL.pAxis^":
+				copy(r.axis, payload)
+			case "GVL.pAxis^.fPos":
+				copy(r.axis[2:6], payload)
+			default:
+				return fail(1809)[0:4]
+			}
+			return make([]byte, 4)
+		case types.ADSReservedIndexGroupSymbolReleaseHandle:
+			delete(r.handles, binary.LittleEndian.Uint32(payload))
+			r.released++
+			return make([]byte, 4)
+		}
+	}
+	return defaultFakeHandler(cmd, port, data)
+}
+
+func TestReferences(t *testing.T) {
+	plc := &referenceRouter{axis: make([]byte, 6), handles: map[uint32]string{}}
+	binary.LittleEndian.PutUint16(plc.axis[0:2], 3)
+	binary.LittleEndian.PutUint32(plc.axis[2:6], math.Float32bits(12.5))
+	router := newFakeRouter(t, plc.handle)
+	c := NewClient(router.settings(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer func() { _ = c.Disconnect() }()
+	axis := map[string]any{"nState": int16(3), "fPos": float32(12.5)}
+
+	t.Run("Reference", func(t *testing.T) {
+		value, err := c.ReadValue(851, "GVL.refAxis")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		assert.Equal(t, axis, value)
+	})
+
+	t.Run("Dereferenced pointer", func(t *testing.T) {
+		value, err := c.ReadValue(851, "GVL.pAxis^")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		assert.Equal(t, axis, value)
+
+		value, err = c.ReadValue(851, "GVL.pAxis^.fPos")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		assert.Equal(t, float32(12.5), value)
+	})
+
+	t.Run("Write", func(t *testing.T) {
+		if err := c.WriteValue(851, "GVL.refAxis", map[string]any{"nState": int16(5), "fPos": float32(1)}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if err := c.WriteValue(851, "GVL.pAxis^.fPos", float32(2.5)); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		value, err := c.ReadValue(851, "GVL.pAxis^")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		assert.Equal(t, map[string]any{"nState": int16(5), "fPos": float32(2.5)}, value)
+	})
+
+	t.Run("Plain variable", func(t *testing.T) {
+		value, err := c.ReadValue(851, "GVL.nCount")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		assert.Equal(t, int16(7), value)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		_, err := c.ReadValue(851, "GVL.nCount^")
+		assert.ErrorContains(t, err, "GVL.nCount is not a pointer or reference")
+		_, err = c.ReadValue(851, "GVL.pAxis^.fSpeed")
+		assert.ErrorContains(t, err, "ST_Axis has no member fSpeed")
+		_, err = c.ReadValue(851, "GVL.pAxis^[1]")
+		assert.ErrorContains(t, err, "unsupported path after dereference")
+
+		// The PLC refuses a handle for nState
+		err = c.WriteValue(851, "GVL.pAxis^.nState", int16(1))
+		assert.ErrorContains(t, err, "WriteValue: failed to create variable handle for GVL.pAxis^.nState")
+	})
+
+	plc.mu.Lock()
+	defer plc.mu.Unlock()
+	assert.Empty(t, plc.handles, "every handle is released")
+	assert.Equal(t, 6, plc.released)
+}
@@ -0,0 +1,140 @@
+package ads
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+)
+
+// registryIndexGroup is the system service index group for
+// HKEY_LOCAL_MACHINE (SYSTEMSERVICE_REGHKEYLOCALMACHINE).
+const registryIndexGroup uint32 = 200
+
+// registryReadBufferSize is the maximum size of a registry value read.
+const registryReadBufferSize = 4096
+
+// RegistryType is the type of a registry value.
+type RegistryType uint32
+
+const (
+	RegistryTypeString RegistryType = 1 // REG_SZ
+	RegistryTypeBinary RegistryType = 3 // REG_BINARY
+	RegistryTypeDword  RegistryType = 4 // REG_DWORD
+)
+
+// String returns the Windows name of the registry type.
+func (t RegistryType) String() string {
+	switch t {
+	case RegistryTypeString:
+		return "REG_SZ"
+	case RegistryTypeBinary:
+		return "REG_BINARY"
+	case RegistryTypeDword:
+		return "REG_DWORD"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// decodeRegistryValue converts the raw content of a registry value of type
+// regType to string (REG_SZ), uint32 (REG_DWORD) or []byte (REG_BINARY).
+func decodeRegistryValue(raw []byte, regType RegistryType) (any, error) {
+	switch regType {
+	case RegistryTypeString:
+		if end := bytes.IndexByte(raw, 0); end >= 0 {
+			return string(raw[:end]), nil
+		}
+		return string(raw), nil
+	case RegistryTypeDword:
+		if len(raw) != 4 {
+			return nil, fmt.Errorf("REG_DWORD needs 4 bytes, got %d", len(raw))
+		}
+		return binary.LittleEndian.Uint32(raw), nil
+	case RegistryTypeBinary:
+		return raw, nil
+	default:
+		return nil, fmt.Errorf("unsupported registry type %d", uint32(regType))
+	}
+}
+
+// registryPayload builds "key\0value\0" with the HKEY_LOCAL_MACHINE prefix
+// removed from key.
+func registryPayload(key, value string) []byte {
+	for _, prefix := range []string{`HKEY_LOCAL_MACHINE\`, `HKLM\`} {
+		if len(key) >= len(prefix) && strings.EqualFold(key[:len(prefix)], prefix) {
+			key = key[len(prefix):]
+			break
+		}
+	}
+	payload := make([]byte, 0, len(key)+len(value)+2)
+	payload = append(payload, key...)
+	payload = append(payload, 0)
+	payload = append(payload, value...)
+	return append(payload, 0)
+}
+
+// ReadRegistry reads value of key below HKEY_LOCAL_MACHINE on the target and
+// decodes it as regType: string (REG_SZ), uint32 (REG_DWORD) or []byte
+// (REG_BINARY). The system service does not report the type of the value, so
+// the caller passes the type it expects; a REG_DWORD that is not 4 bytes long
+// is an error.
+//
+// Example:
+//
+//	v, err := client.ReadRegistry(`SOFTWARE\WOW6432Node\Beckhoff\TwinCAT3\System`, "AmsNetId", ads.RegistryTypeBinary)
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Printf("% x\n", v.([]byte))
+func (c *Client) ReadRegistry(key, value string, regType RegistryType) (any, error) {
+	c.logger.Debug("ReadRegistry: Reading registry value", "key", key, "value", value, "type", regType.String())
+	switch regType {
+	case RegistryTypeString, RegistryTypeDword, RegistryTypeBinary:
+	default:
+		return nil, fmt.Errorf("ReadRegistry: unsupported registry type %d", uint32(regType))
+	}
+
+	// ReadWriteRaw appends the terminator of the value name
+	payload := registryPayload(key, value)
+	data, err := c.ReadWriteRaw(types.ADSReservedPortSystemService, registryIndexGroup, 0, registryReadBufferSize, payload[:len(payload)-1])
+	if err != nil {
+		c.logger.Error("ReadRegistry: Failed to read registry value", "key", key, "value", value, "error", err)
+		return nil, fmt.Errorf("ReadRegistry: failed to read %s\\%s: %w", key, value, err)
+	}
+	decoded, err := decodeRegistryValue(data, regType)
+	if err != nil {
+		return nil, fmt.Errorf("ReadRegistry: %s\\%s: %w", key, value, err)
+	}
+	return decoded, nil
+}
+
+// WriteRegistry writes data to value of key below HKEY_LOCAL_MACHINE on the
+// target. The registry type follows from the Go type of data: string
+// (REG_SZ), uint32 (REG_DWORD) or []byte (REG_BINARY).
+func (c *Client) WriteRegistry(key, value string, data any) error {
+	c.logger.Debug("WriteRegistry: Writing registry value", "key", key, "value", value)
+
+	var regType RegistryType
+	var raw []byte
+	switch d := data.(type) {
+	case string:
+		regType, raw = RegistryTypeString, append([]byte(d), 0)
+	case uint32:
+		regType, raw = RegistryTypeDword, binary.LittleEndian.AppendUint32(nil, d)
+	case []byte:
+		regType, raw = RegistryTypeBinary, d
+	default:
+		return fmt.Errorf("WriteRegistry: unsupported data type %T (use string, uint32 or []byte)", data)
+	}
+
+	payload := append(registryPayload(key, value), raw...)
+	if err := c.WriteRaw(types.ADSReservedPortSystemService, registryIndexGroup, uint32(regType), payload); err != nil {
+		c.logger.Error("WriteRegistry: Failed to write registry value", "key", key, "value", value, "error", err)
+		return fmt.Errorf("WriteRegistry: failed to write %s\\%s: %w", key, value, err)
+	}
+	c.logger.Info("WriteRegistry: Registry value written", "key", key, "value", value, "type", regType.String())
+	return nil
+}
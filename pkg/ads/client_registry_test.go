@@ -0,0 +1,97 @@
+package ads
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRegistry verifies the registry requests and the value decoding.
+func TestRegistry(t *testing.T) {
+	var mu sync.Mutex
+	values := map[string][]byte{
+		`SOFTWARE\Beckhoff\TwinCAT3\System\AmsNetId`:     {10, 0, 0, 1, 1, 1},
+		`SOFTWARE\Beckhoff\TwinCAT3\System\RouterMemory`: {0, 0, 0, 2},
+		`SOFTWARE\Beckhoff\TwinCAT3\System\Name`:         []byte("PLC-1\x00"),
+	}
+	var writtenType uint32
+	router := newFakeRouter(t, func(cmd types.ADSCommand, port uint16, data []byte) []byte {
+		if port != uint16(types.ADSReservedPortSystemService) || binary.LittleEndian.Uint32(data[0:4]) != registryIndexGroup {
+			return defaultFakeHandler(cmd, port, data)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch cmd {
+		case types.ADSCommandReadWrite:
+			parts := bytes.Split(data[16:], []byte{0})
+			value, ok := values[string(parts[0])+`\`+string(parts[1])]
+			if !ok {
+				resp := make([]byte, 8)
+				binary.LittleEndian.PutUint32(resp[0:4], 1804) // not found
+				return resp
+			}
+			resp := make([]byte, 8, 8+len(value))
+			binary.LittleEndian.PutUint32(resp[4:8], uint32(len(value)))
+			return append(resp, value...)
+		case types.ADSCommandWrite:
+			writtenType = binary.LittleEndian.Uint32(data[4:8])
+			parts := bytes.SplitN(data[12:], []byte{0}, 3)
+			values[string(parts[0])+`\`+string(parts[1])] = parts[2]
+			return make([]byte, 4)
+		}
+		return defaultFakeHandler(cmd, port, data)
+	})
+	c := NewClient(router.settings(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer func() { _ = c.Disconnect() }()
+
+	const key = `HKLM\SOFTWARE\Beckhoff\TwinCAT3\System`
+	netID, err := c.ReadRegistry(key, "AmsNetId", RegistryTypeBinary)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, []byte{10, 0, 0, 1, 1, 1}, netID)
+
+	size, err := c.ReadRegistry(key, "RouterMemory", RegistryTypeDword)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, uint32(0x02000000), size)
+
+	name, err := c.ReadRegistry(key, "Name", RegistryTypeString)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, "PLC-1", name)
+	_, err = c.ReadRegistry(key, "Name", RegistryTypeDword)
+	assert.ErrorContains(t, err, "REG_DWORD needs 4 bytes, got 6", "REG_SZ is not a REG_DWORD")
+	_, err = c.ReadRegistry(key, "Name", RegistryType(7))
+	assert.ErrorContains(t, err, "unsupported registry type 7")
+
+	_, err = c.ReadRegistry(key, "Missing", RegistryTypeString)
+	assert.Error(t, err)
+
+	if err := c.WriteRegistry(key, "RouterMemory", uint32(64)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, uint32(RegistryTypeDword), writtenType)
+	if err := c.WriteRegistry(key, "Name", "PLC-2"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, uint32(RegistryTypeString), writtenType)
+	name, err = c.ReadRegistry(key, "Name", RegistryTypeString)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, "PLC-2", name)
+
+	assert.Error(t, c.WriteRegistry(key, "Name", 1.5), "unsupported type")
+}
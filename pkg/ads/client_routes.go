@@ -0,0 +1,149 @@
+package ads
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+	"github.com/jarmocluyse/ads-go/pkg/ads/utils"
+)
+
+// System service index groups of the route table.
+const (
+	routeDeleteIndexGroup uint32 = 802 // SYSTEMSERVICE_DELREMOTE
+	routeEnumIndexGroup   uint32 = 803 // SYSTEMSERVICE_ENUMREMOTE
+)
+
+// routeEntrySize is the read length of a route entry.
+const routeEntrySize = 2048
+
+// routeHeaderSize is the size of a route entry before the strings.
+const routeHeaderSize = 32
+
+// maxRoutes bounds the enumeration in case the target never reports the end.
+const maxRoutes = 1024
+
+// RouteTransport is the transport type of an AMS route.
+type RouteTransport uint32
+
+const (
+	RouteTransportNone   RouteTransport = 0
+	RouteTransportTCPIP  RouteTransport = 1
+	RouteTransportUDP    RouteTransport = 5
+	RouteTransportSerial RouteTransport = 7
+	RouteTransportUSB    RouteTransport = 8
+)
+
+// String returns the name of the transport type.
+func (t RouteTransport) String() string {
+	switch t {
+	case RouteTransportNone:
+		return "None"
+	case RouteTransportTCPIP:
+		return "TCP/IP"
+	case RouteTransportUDP:
+		return "UDP"
+	case RouteTransportSerial:
+		return "Serial"
+	case RouteTransportUSB:
+		return "USB"
+	default:
+		return fmt.Sprintf("Transport(%d)", uint32(t))
+	}
+}
+
+// Route is an entry of the AMS route table of the target.
+type Route struct {
+	Name      string         // route name
+	NetID     string         // AMS net id of the remote system
+	Address   string         // host name or IP address of the remote system
+	Transport RouteTransport // transport type
+	Timeout   time.Duration  // route timeout (0 = default)
+	Flags     uint32         // raw route flags
+}
+
+// ReadRoutes returns the routes configured on the target.
+//
+// Example:
+//
+//	routes, err := client.ReadRoutes()
+//	if err != nil {
+//	    return err
+//	}
+//	for _, r := range routes {
+//	    fmt.Printf("%-20s %-18s %s\n", r.Name, r.NetID, r.Address)
+//	}
+func (c *Client) ReadRoutes() ([]Route, error) {
+	c.logger.Debug("ReadRoutes: Enumerating routes")
+
+	var routes []Route
+	for index := uint32(0); index < maxRoutes; index++ {
+		data, err := c.ReadRaw(types.ADSReservedPortSystemService, routeEnumIndexGroup, index, routeEntrySize)
+		if isAdsNotFound(err) {
+			break // end of the route table
+		}
+		if err != nil {
+			c.logger.Error("ReadRoutes: Failed to read route", "index", index, "error", err)
+			return nil, fmt.Errorf("ReadRoutes: failed to read route %d: %w", index, err)
+		}
+
+		route, err := parseRoute(data)
+		if err != nil {
+			return nil, fmt.Errorf("ReadRoutes: route %d: %w", index, err)
+		}
+		routes = append(routes, route)
+	}
+
+	c.logger.Info("ReadRoutes: Routes read", "count", len(routes))
+	return routes, nil
+}
+
+// RemoveRoute removes the route with name from the target.
+func (c *Client) RemoveRoute(name string) error {
+	c.logger.Debug("RemoveRoute: Removing route", "name", name)
+	if name == "" {
+		return fmt.Errorf("RemoveRoute: route name is empty")
+	}
+
+	data := append([]byte(name), 0)
+	if err := c.WriteRaw(types.ADSReservedPortSystemService, routeDeleteIndexGroup, 0, data); err != nil {
+		c.logger.Error("RemoveRoute: Failed to remove route", "name", name, "error", err)
+		return fmt.Errorf("RemoveRoute: failed to remove route %q: %w", name, err)
+	}
+	c.logger.Info("RemoveRoute: Route removed", "name", name)
+	return nil
+}
+
+// parseRoute decodes a route entry:
+//
+//	[0..5]   AMS net id
+//	[6..7]   Reserved
+//	[8..11]  Transport type (uint32)
+//	[12..15] Timeout in ms (uint32)
+//	[16..19] Flags (uint32)
+//	[20..23] Reserved
+//	[24..27] Address length including the terminator (uint32)
+//	[28..31] Name length including the terminator (uint32)
+//	[32..]   Address, then name (null-terminated)
+func parseRoute(data []byte) (Route, error) {
+	if len(data) < routeHeaderSize {
+		return Route{}, fmt.Errorf("route entry too short: %d bytes", len(data))
+	}
+	addressLen := int(binary.LittleEndian.Uint32(data[24:28]))
+	nameLen := int(binary.LittleEndian.Uint32(data[28:32]))
+	if routeHeaderSize+addressLen+nameLen > len(data) {
+		return Route{}, fmt.Errorf("route entry strings exceed the entry: %d + %d bytes", addressLen, nameLen)
+	}
+	address := data[routeHeaderSize : routeHeaderSize+addressLen]
+	name := data[routeHeaderSize+addressLen : routeHeaderSize+addressLen+nameLen]
+
+	return Route{
+		Name:      utils.DecodePlcStringBuffer(name),
+		NetID:     utils.ByteArrayToAmsNetIdStr(data[0:6]),
+		Address:   utils.DecodePlcStringBuffer(address),
+		Transport: RouteTransport(binary.LittleEndian.Uint32(data[8:12])),
+		Timeout:   time.Duration(binary.LittleEndian.Uint32(data[12:16])) * time.Millisecond,
+		Flags:     binary.LittleEndian.Uint32(data[16:20]),
+	}, nil
+}
@@ -0,0 +1,94 @@
+package ads
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// routeEntry builds a route entry as returned by the system service.
+func routeEntry(name string, netID []byte, address string, transport RouteTransport) []byte {
+	data := make([]byte, routeHeaderSize)
+	copy(data[0:6], netID)
+	binary.LittleEndian.PutUint32(data[8:12], uint32(transport))
+	binary.LittleEndian.PutUint32(data[12:16], 5000)
+	binary.LittleEndian.PutUint32(data[16:20], 1)
+	binary.LittleEndian.PutUint32(data[24:28], uint32(len(address)+1))
+	binary.LittleEndian.PutUint32(data[28:32], uint32(len(name)+1))
+	data = append(append(data, address...), 0)
+	return append(append(data, name...), 0)
+}
+
+func TestRoutes(t *testing.T) {
+	var mu sync.Mutex
+	routes := [][]byte{
+		routeEntry("ENG-LAPTOP-07", []byte{192, 168, 1, 50, 1, 1}, "192.168.1.50", RouteTransportTCPIP),
+		routeEntry("MES", []byte{10, 0, 0, 20, 1, 1}, "mes.plant.local", RouteTransportTCPIP),
+	}
+	router := newFakeRouter(t, func(cmd types.ADSCommand, port uint16, data []byte) []byte {
+		if port != uint16(types.ADSReservedPortSystemService) {
+			return defaultFakeHandler(cmd, port, data)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case cmd == types.ADSCommandRead && binary.LittleEndian.Uint32(data[0:4]) == routeEnumIndexGroup:
+			index := binary.LittleEndian.Uint32(data[4:8])
+			if int(index) >= len(routes) {
+				resp := make([]byte, 8)
+				binary.LittleEndian.PutUint32(resp[0:4], 1804) // not found
+				return resp
+			}
+			resp := make([]byte, 8)
+			binary.LittleEndian.PutUint32(resp[4:8], uint32(len(routes[index])))
+			return append(resp, routes[index]...)
+		case cmd == types.ADSCommandWrite && binary.LittleEndian.Uint32(data[0:4]) == routeDeleteIndexGroup:
+			name := bytes.TrimRight(data[12:], "\x00")
+			for i, route := range routes {
+				if parsed, _ := parseRoute(route); parsed.Name == string(name) {
+					routes = append(routes[:i], routes[i+1:]...)
+					return make([]byte, 4)
+				}
+			}
+			resp := make([]byte, 4)
+			binary.LittleEndian.PutUint32(resp, 1804)
+			return resp
+		}
+		return defaultFakeHandler(cmd, port, data)
+	})
+	c := NewClient(router.settings(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer func() { _ = c.Disconnect() }()
+
+	list, err := c.ReadRoutes()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, []Route{
+		{Name: "ENG-LAPTOP-07", NetID: "192.168.1.50.1.1", Address: "192.168.1.50", Transport: RouteTransportTCPIP, Timeout: 5 * time.Second, Flags: 1},
+		{Name: "MES", NetID: "10.0.0.20.1.1", Address: "mes.plant.local", Transport: RouteTransportTCPIP, Timeout: 5 * time.Second, Flags: 1},
+	}, list)
+
+	if err := c.RemoveRoute("ENG-LAPTOP-07"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	list, err = c.ReadRoutes()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Len(t, list, 1)
+	assert.Equal(t, "MES", list[0].Name)
+
+	assert.Error(t, c.RemoveRoute("ENG-LAPTOP-07"), "route is gone")
+	assert.Error(t, c.RemoveRoute(""))
+	assert.Equal(t, "TCP/IP", RouteTransportTCPIP.String())
+}
@@ -1,6 +1,8 @@
 package ads
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -15,11 +17,47 @@ type AdsCommandRequest struct {
 	Data       []byte           // data to send
 }
 
+// unansweredError is returned by transmitContext when the command was written
+// but no response arrived (timeout, connection loss or ctx ended).
+type unansweredError struct {
+	err error
+}
+
+func (e *unansweredError) Error() string { return e.err.Error() }
+func (e *unansweredError) Unwrap() error { return e.err }
+
 // send sends a command to the ADS router.
+// Returns ErrClientClosed once Close has been called.
 func (c *Client) send(req AdsCommandRequest) ([]byte, error) {
-	if c.conn == nil {
-		c.logger.Error("send: Connection is nil, cannot send command")
-		return nil, fmt.Errorf("connection is not established")
+	return c.sendContext(context.Background(), req)
+}
+
+// sendContext is send that stops waiting for the response when ctx ends.
+func (c *Client) sendContext(ctx context.Context, req AdsCommandRequest) ([]byte, error) {
+	if err := c.beginRequest(); err != nil {
+		c.logger.Error("send: Cannot send command on a closing client", "error", err)
+		return nil, err
+	}
+	defer c.inFlight.Done()
+	return c.transmitContext(ctx, req)
+}
+
+// transmit writes the command to the connection and waits for the response.
+// It bypasses the Close gate and is used directly by Close for teardown requests.
+func (c *Client) transmit(req AdsCommandRequest) ([]byte, error) {
+	return c.transmitContext(context.Background(), req)
+}
+
+// transmitContext is transmit that stops waiting for the response when ctx
+// ends. The command is not written if ctx has already ended.
+func (c *Client) transmitContext(ctx context.Context, req AdsCommandRequest) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	conn, localAddr, err := c.activeConn()
+	if err != nil {
+		c.logger.Error("send: Cannot send command without an active connection", "error", err)
+		return nil, err
 	}
 	c.logger.Debug("send: Preparing to send command", "command", req.Command.String(), "dataLength", len(req.Data))
 
@@ -29,7 +67,7 @@ func (c *Client) send(req AdsCommandRequest) ([]byte, error) {
 	target := AmsAddress{NetID: c.settings.TargetNetID, Port: req.TargetPort}
 	c.logger.Debug("send: Target AMS Address", "netID", target.NetID, "port", target.Port)
 
-	amsHeader, err := amsbuilder.BuildAmsHeader(target, c.localAmsAddr, req.Command, uint32(len(req.Data)), invokeID)
+	amsHeader, err := amsbuilder.BuildAmsHeader(target, localAddr, req.Command, uint32(len(req.Data)), invokeID)
 	if err != nil {
 		c.logger.Error("send: Failed to create AMS header", "error", err)
 		return nil, err
@@ -41,7 +79,7 @@ func (c *Client) send(req AdsCommandRequest) ([]byte, error) {
 	packet = append(packet, req.Data...)
 	c.logger.Debug("send: Constructed packet", "totalLength", len(packet), "packet", fmt.Sprintf("%x", packet))
 
-	_, err = c.conn.Write(packet)
+	_, err = conn.Write(packet)
 	if err != nil {
 		c.logger.Error("send: Failed to write packet to connection", "error", err)
 		return nil, err
@@ -51,12 +89,18 @@ func (c *Client) send(req AdsCommandRequest) ([]byte, error) {
 	select {
 	case response := <-channel:
 		c.logger.Debug("send: Received response", "invokeID", invokeID, "response", response)
+		if errors.Is(response.Error, ErrNotConnected) {
+			return nil, &unansweredError{err: response.Error}
+		}
 		if response.Error != nil {
 			return nil, response.Error
 		}
 		return response.Data, nil
 	case <-time.After(c.settings.Timeout):
 		c.logger.Warn("send: Timeout waiting for response", "invokeID", invokeID)
-		return nil, fmt.Errorf("timeout waiting for response")
+		return nil, &unansweredError{err: ErrTimeout}
+	case <-ctx.Done():
+		c.logger.Warn("send: Context ended while waiting for response", "invokeID", invokeID, "error", ctx.Err())
+		return nil, &unansweredError{err: ctx.Err()}
 	}
 }
@@ -1,12 +1,14 @@
 package ads
 
+import "time"
+
 // create a new invoke id and make a channel for it
 func (c *Client) getInvokeID() (uint32, chan Response) {
 	c.mutex.Lock()
 	c.invokeID++
 	id := c.invokeID
-	ch := make(chan Response)
-	c.requests[id] = ch
+	ch := make(chan Response, 1) // buffered so a late response never blocks the receiver
+	c.requests[id] = &pendingRequest{ch: ch, sentAt: time.Now()}
 	c.mutex.Unlock()
 	c.logger.Debug("send: Assigned InvokeID", "invokeID", id)
 	return id, ch
@@ -147,49 +147,44 @@ func (c *Client) checkState(pollerID int) {
 
 	// Handle read failure with consecutive failure counter
 	if readErr != nil {
+		c.stateMutex.Lock()
 		c.consecutiveReadFailures++
+		count := c.consecutiveReadFailures
+		limitReached := count >= c.settings.MaxConsecutiveReadFailures
+		if limitReached {
+			c.consecutiveReadFailures = 0
+		}
+		c.stateMutex.Unlock()
+
 		c.logger.Warn("checkState: Failed to read system state",
 			"error", readErr,
-			"consecutiveFailures", c.consecutiveReadFailures,
+			"consecutiveFailures", count,
 			"maxFailures", c.settings.MaxConsecutiveReadFailures)
 
-		if c.consecutiveReadFailures >= c.settings.MaxConsecutiveReadFailures {
-			count := c.consecutiveReadFailures
-			c.consecutiveReadFailures = 0
+		if limitReached {
 			c.logger.Warn("checkState: Consecutive failure limit reached, triggering connection lost",
 				"consecutiveFailures", count)
-			c.invokeConnectionLostHook(fmt.Errorf("remote target unreachable after %d consecutive failures: %w", count, readErr))
+			c.handleConnectionLost(nil, fmt.Errorf("remote target unreachable after %d consecutive failures: %w", count, readErr))
 			return // Don't reschedule — let reconnect restart the poller
 		}
 
 		// Still within tolerance — keep polling at normal interval
+		c.updateTargetHealth(false)
 		c.scheduleNextStateCheck(pollerID)
 		return
 	}
 
-	// Successful read — reset failure counter
-	c.consecutiveReadFailures = 0
-
-	// Update the cached state
+	// Successful read — reset failure counter and update the cached state
 	c.stateMutex.Lock()
-	c.currentState = newState
+	c.consecutiveReadFailures = 0
 	c.stateMutex.Unlock()
+	changed := c.applySystemState(newState, "checkState")
+	if newState.AdsState == types.ADSStateRun {
+		c.checkPlcStates()
+		c.checkSymbolVersions()
+	}
 
-	// Detect state changes
-	if oldState == nil {
-		// First state read
-		c.logger.Info("checkState: Initial state read", "state", newState.AdsState.String())
-		c.invokeStateChangeHook(newState, nil)
-	} else if newState.AdsState != oldState.AdsState {
-		// State changed
-		c.logger.Info("checkState: TwinCAT state changed",
-			"from", oldState.AdsState.String(),
-			"to", newState.AdsState.String())
-
-		// OnStateChange fires for every transition (Run→Config, Config→Run, etc.).
-		// Callers use it to clean up subscriptions/symbols when TC leaves Run.
-		c.invokeStateChangeHook(newState, oldState)
-	} else if newRestartIndex != nil && oldRestartIndex != nil && *newRestartIndex != *oldRestartIndex {
+	if !changed && oldState != nil && newRestartIndex != nil && oldRestartIndex != nil && *newRestartIndex != *oldRestartIndex {
 		// Restart detected - restart index changed but ADS state stayed the same
 		c.logger.Info("checkState: TwinCAT system restarted (restart index changed)",
 			"state", newState.AdsState.String(),
@@ -201,7 +196,7 @@ func (c *Client) checkState(pollerID int) {
 
 		// Trigger connection lost to allow user to re-read values and re-subscribe
 		// Don't schedule next check - let user handle reconnection in hook
-		c.invokeConnectionLostHook(fmt.Errorf("TwinCAT system restarted (restart index: %d → %d)", *oldRestartIndex, *newRestartIndex))
+		c.handleConnectionLost(nil, fmt.Errorf("TwinCAT system restarted (restart index: %d → %d)", *oldRestartIndex, *newRestartIndex))
 		return
 	}
 
@@ -209,6 +204,36 @@ func (c *Client) checkState(pollerID int) {
 	c.scheduleNextStateCheck(pollerID)
 }
 
+// applySystemState caches newState, updates the target health and fires
+// OnStateChange if the ADS state differs from the cached one. It is fed by
+// the state poller and by state notifications. Returns true if OnStateChange
+// was fired. source names the caller in the log.
+func (c *Client) applySystemState(newState *adsstateinfo.SystemState, source string) bool {
+	c.stateMutex.Lock()
+	oldState := c.currentState
+	c.currentState = newState
+	c.stateMutex.Unlock()
+	c.updateTargetHealth(newState.AdsState == types.ADSStateRun)
+
+	switch {
+	case oldState == nil:
+		// First state read
+		c.logger.Info(source+": Initial state read", "state", newState.AdsState.String())
+		c.invokeStateChangeHook(newState, nil)
+	case newState.AdsState != oldState.AdsState:
+		c.logger.Info(source+": TwinCAT state changed",
+			"from", oldState.AdsState.String(),
+			"to", newState.AdsState.String())
+
+		// OnStateChange fires for every transition (Run→Config, Config→Run, etc.).
+		// Callers use it to clean up subscriptions/symbols when TC leaves Run.
+		c.invokeStateChangeHook(newState, oldState)
+	default:
+		return false
+	}
+	return true
+}
+
 // scheduleNextStateCheck schedules the next state check after the configured polling interval.
 func (c *Client) scheduleNextStateCheck(pollerID int) {
 	c.statePollerMutex.Lock()
@@ -229,10 +254,17 @@ func (c *Client) scheduleNextStateCheck(pollerID int) {
 }
 
 // invokeConnectionLostHook clears the cached state and calls the OnConnectionLost hook.
+// Use handleConnectionLost to also tear down the connection.
 func (c *Client) invokeConnectionLostHook(err error) {
 	c.stateMutex.Lock()
 	c.currentState = nil
 	c.stateMutex.Unlock()
+	c.clearPlcStates()
+
+	if c.targetShutdown.Load() {
+		c.logger.Info("invokeConnectionLostHook: Connection lost by requested target shutdown, not calling OnConnectionLost", "reason", err)
+		return
+	}
 
 	go c.invokeHook("OnConnectionLost", func() {
 		c.settings.OnConnectionLost(c, err)
@@ -250,16 +282,17 @@ func (c *Client) invokeStateChangeHook(newState, oldState *adsstateinfo.SystemSt
 	})
 }
 
-// checkStateForOperation verifies that the system is in Run mode before performing read/write.
-// Returns error if not in Run mode. Logs warning if state is unknown.
-func (c *Client) checkStateForOperation(operationName string) error {
+// checkStateForOperation verifies that the system and the PLC runtime on port
+// are in Run mode before performing read/write. Returns error if not in Run
+// mode. Logs warning if state is unknown.
+func (c *Client) checkStateForOperation(operationName string, port uint16) error {
 	c.stateMutex.RLock()
 	state := c.currentState
 	c.stateMutex.RUnlock()
 
 	if state == nil {
 		c.logger.Debug(operationName + ": System state unknown, allowing operation")
-		return nil // Allow operation - state might not be cached yet
+		return c.checkPlcStateForOperation(operationName, port) // state might not be cached yet
 	}
 
 	if state.AdsState != types.ADSStateRun {
@@ -267,5 +300,5 @@ func (c *Client) checkStateForOperation(operationName string) error {
 			operationName, state.AdsState.String())
 	}
 
-	return nil
+	return c.checkPlcStateForOperation(operationName, port)
 }
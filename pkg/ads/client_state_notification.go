@@ -0,0 +1,77 @@
+package ads
+
+import (
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+)
+
+// startStateNotification subscribes to the device state of the system
+// service if ClientSettings.StateNotifications is set. The notification
+// sends [0..1] ADS state and [2..3] device state on every change. On failure
+// the state poller remains the only source.
+func (c *Client) startStateNotification() {
+	if !c.settings.StateNotifications {
+		return
+	}
+
+	sub, err := c.addSubscription(subscriptionSpec{
+		port:        uint16(types.ADSReservedPortSystemService),
+		indexGroup:  uint32(types.ADSReservedIndexGroupDeviceData),
+		indexOffset: 0,
+		size:        4,
+		callback:    c.handleStateNotification,
+		settings: SubscriptionSettings{
+			CycleTime:        c.settings.StateNotificationCycleTime,
+			TransmissionMode: types.ADSTransModeOnChange,
+		},
+		isRaw:    true,
+		internal: true,
+	})
+	if err != nil {
+		c.logger.Warn("startStateNotification: Device state notification not available, using polling only", "error", err)
+		return
+	}
+
+	c.statePollerMutex.Lock()
+	c.stateSubscription = sub
+	c.statePollerMutex.Unlock()
+	c.logger.Info("startStateNotification: Monitoring state with notifications", "handle", sub.Handle, "cycleTime", c.settings.StateNotificationCycleTime)
+
+	for _, port := range c.trackedPlcPorts() {
+		c.startPlcStateNotification(port)
+	}
+}
+
+// stopStateNotification deletes the device state notifications of the
+// system service and the runtime ports with send. If that fails (e.g. the
+// connection is gone) they are only removed locally.
+func (c *Client) stopStateNotification(send func(AdsCommandRequest) ([]byte, error)) {
+	c.statePollerMutex.Lock()
+	subs := make([]*ActiveSubscription, 0, 1+len(c.plcStateSubscriptions))
+	if c.stateSubscription != nil {
+		subs = append(subs, c.stateSubscription)
+	}
+	for _, sub := range c.plcStateSubscriptions {
+		subs = append(subs, sub)
+	}
+	c.stateSubscription = nil
+	clear(c.plcStateSubscriptions)
+	c.statePollerMutex.Unlock()
+
+	for _, sub := range subs {
+		if err := c.unsubscribe(sub, send); err != nil {
+			c.logger.Debug("stopStateNotification: Failed to delete notification, removing locally", "port", sub.Port, "error", err)
+			c.removeSubscription(sub)
+			sub.close(ErrSubscriptionClosed)
+		}
+	}
+}
+
+// handleStateNotification feeds a device state notification into the cached state.
+func (c *Client) handleStateNotification(data SubscriptionData) {
+	state, ok := parseStateNotification(data)
+	if !ok {
+		c.logger.Warn("handleStateNotification: Invalid state notification", "length", len(data.RawValue))
+		return
+	}
+	c.applySystemState(state, "handleStateNotification")
+}
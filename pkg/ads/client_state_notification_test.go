@@ -0,0 +1,73 @@
+package ads
+
+import (
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	adsstateinfo "github.com/jarmocluyse/ads-go/pkg/ads/ads-stateinfo"
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStateNotification verifies that device state notifications feed
+// OnStateChange and that the internal subscription is hidden from the user.
+func TestStateNotification(t *testing.T) {
+	var statePorts, deletes atomic.Int32
+	router := newFakeRouter(t, func(cmd types.ADSCommand, port uint16, data []byte) []byte {
+		switch cmd {
+		case types.ADSCommandAddNotification:
+			if port == uint16(types.ADSReservedPortSystemService) &&
+				binary.LittleEndian.Uint32(data[0:4]) == uint32(types.ADSReservedIndexGroupDeviceData) {
+				statePorts.Add(1)
+			}
+			resp := make([]byte, 8)
+			binary.LittleEndian.PutUint32(resp[4:8], 5)
+			return resp
+		case types.ADSCommandDeleteNotification:
+			deletes.Add(1)
+		}
+		return defaultFakeHandler(cmd, port, data)
+	})
+
+	changes := make(chan types.ADSState, 4)
+	settings := router.settings()
+	settings.StateNotifications = true
+	settings.OnStateChange = func(_ *Client, newState, _ *adsstateinfo.SystemState) {
+		changes <- newState.AdsState
+	}
+	c := NewClient(settings, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, types.ADSStateRun, <-changes, "initial state from Connect")
+	assert.Equal(t, int32(1), statePorts.Load())
+	assert.Empty(t, c.Debug().Subscriptions, "state notification is internal")
+
+	state := make([]byte, 4)
+	binary.LittleEndian.PutUint16(state[0:2], uint16(types.ADSStateStop))
+	router.notify(uint16(types.ADSReservedPortSystemService), 5, state)
+	select {
+	case got := <-changes:
+		assert.Equal(t, types.ADSStateStop, got)
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting for OnStateChange")
+	}
+	waitFor(t, time.Second, func() bool { return c.ConnectionState() == ConnectionStateDegraded }, "degraded state")
+
+	// The same state again is not a change
+	router.notify(uint16(types.ADSReservedPortSystemService), 5, state)
+	select {
+	case got := <-changes:
+		t.Fatalf("Expected no state change, got %v", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := c.Disconnect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, int32(1), deletes.Load(), "state notification deleted on disconnect")
+}
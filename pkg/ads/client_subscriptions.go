@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	adsserializer "github.com/jarmocluyse/ads-go/pkg/ads/ads-serializer"
 	adssymbol "github.com/jarmocluyse/ads-go/pkg/ads/ads-symbol"
 	"github.com/jarmocluyse/ads-go/pkg/ads/types"
 )
@@ -27,8 +28,20 @@ import (
 //	    },
 //	)
 func (c *Client) SubscribeValue(port uint16, path string, callback SubscriptionCallback, settings SubscriptionSettings) (*ActiveSubscription, error) {
+	return c.subscribeValue(port, path, callback, settings, nil)
+}
+
+// subscribeValue resolves the symbol and data type of path and subscribes to it.
+func (c *Client) subscribeValue(port uint16, path string, callback SubscriptionCallback, settings SubscriptionSettings, onTerminate func(err error)) (*ActiveSubscription, error) {
 	c.logger.Debug("SubscribeValue: Subscribing to value", "port", port, "path", path)
 
+	if settings.Multiplex {
+		sub, ok, err := c.subscribeMultiplexed(port, path, callback, settings, onTerminate)
+		if ok {
+			return sub, err
+		}
+	}
+
 	// Get symbol info (like ReadValue does)
 	symbol, err := c.GetSymbol(port, path)
 	if err != nil {
@@ -43,7 +56,17 @@ func (c *Client) SubscribeValue(port uint16, path string, callback SubscriptionC
 	}
 
 	// Subscribe using raw address with symbol and data type info
-	return c.addSubscription(port, symbol.IndexGroup, symbol.IndexOffset, symbol.Size, callback, settings, symbol, &dataType, false)
+	return c.addSubscription(subscriptionSpec{
+		port:        port,
+		indexGroup:  symbol.IndexGroup,
+		indexOffset: symbol.IndexOffset,
+		size:        symbol.Size,
+		callback:    callback,
+		settings:    settings,
+		symbol:      symbol,
+		dataType:    &dataType,
+		onTerminate: onTerminate,
+	})
 }
 
 // SubscribeRaw subscribes to a variable by raw ADS address (no parsing).
@@ -64,59 +87,137 @@ func (c *Client) SubscribeValue(port uint16, path string, callback SubscriptionC
 //	)
 func (c *Client) SubscribeRaw(port uint16, indexGroup, indexOffset, size uint32, callback SubscriptionCallback, settings SubscriptionSettings) (*ActiveSubscription, error) {
 	c.logger.Debug("SubscribeRaw: Subscribing to raw address", "port", port, "indexGroup", indexGroup, "indexOffset", indexOffset, "size", size)
-	return c.addSubscription(port, indexGroup, indexOffset, size, callback, settings, nil, nil, true)
+	return c.addSubscription(subscriptionSpec{
+		port:        port,
+		indexGroup:  indexGroup,
+		indexOffset: indexOffset,
+		size:        size,
+		callback:    callback,
+		settings:    settings,
+		isRaw:       true,
+	})
 }
 
-// addSubscription is the internal method that sends the AddNotification command.
-func (c *Client) addSubscription(port uint16, indexGroup, indexOffset, size uint32, callback SubscriptionCallback, settings SubscriptionSettings, symbol *adssymbol.AdsSymbol, dataType *types.AdsDataType, isRaw bool) (*ActiveSubscription, error) {
-	c.logger.Debug("addSubscription: Creating subscription", "port", port, "indexGroup", indexGroup, "indexOffset", indexOffset)
+// subscriptionSpec describes a subscription before it is created.
+type subscriptionSpec struct {
+	port        uint16
+	indexGroup  uint32
+	indexOffset uint32
+	size        uint32
+	callback    SubscriptionCallback
+	settings    SubscriptionSettings
+	symbol      *adssymbol.AdsSymbol // nil for raw subscriptions
+	dataType    *types.AdsDataType   // nil for raw subscriptions
+	isRaw       bool
+	onTerminate func(err error) // optional, called once when the subscription ends
+	internal    bool            // used by the client itself (state notifications)
+}
 
-	// Apply defaults
-	if settings.CycleTime == 0 {
-		settings.CycleTime = 200 * time.Millisecond
+// prepare applies the setting defaults and validates the settings. opts are
+// the serializer options the values are converted with.
+func (spec *subscriptionSpec) prepare(opts adsserializer.Options) error {
+	if err := spec.prepareSettings(); err != nil {
+		return err
 	}
+	return spec.validateFilter(opts)
+}
+
+// prepareSettings applies the setting defaults and validates the settings
+// that do not depend on the data type.
+func (spec *subscriptionSpec) prepareSettings() error {
+	if spec.settings.CycleTime == 0 {
+		spec.settings.CycleTime = 200 * time.Millisecond
+	}
+	if spec.settings.QueueSize == 0 {
+		spec.settings.QueueSize = DefaultQueueSize
+	}
+	return spec.settings.Validate()
+}
+
+// validateFilter checks that a value filter is applied to a numeric variable.
+func (spec *subscriptionSpec) validateFilter(opts adsserializer.Options) error {
+	if spec.settings.Filter.usesValue() && (spec.isRaw || spec.dataType == nil || !isNumericDataType(*spec.dataType, opts)) {
+		return fmt.Errorf("invalid Filter: deadband and hysteresis require a numeric variable subscribed by path")
+	}
+	return nil
+}
+
+// newSubscription creates the ActiveSubscription for a notification handle.
+func (spec *subscriptionSpec) newSubscription(handle uint32) *ActiveSubscription {
+	return &ActiveSubscription{
+		Handle:      handle,
+		Port:        spec.port,
+		Symbol:      spec.symbol,
+		DataType:    spec.dataType,
+		Settings:    spec.settings,
+		Callback:    spec.callback,
+		IsRaw:       spec.isRaw,
+		indexGroup:  spec.indexGroup,
+		indexOffset: spec.indexOffset,
+		size:        spec.size,
+		onTerminate: spec.onTerminate,
+		internal:    spec.internal,
+	}
+}
+
+// addNotificationPayload builds the 40-byte AddNotification request.
+// The settings must have been validated by prepare.
+func (spec *subscriptionSpec) addNotificationPayload() []byte {
+	maxDelayUnits, _ := durationTo100ns(spec.settings.MaxDelay)
+	cycleTimeUnits, _ := durationTo100ns(spec.settings.CycleTime)
 
-	// Build 40-byte AddNotification request payload
 	payload := make([]byte, 40)
 	pos := 0
 
 	// 0..3 IndexGroup
-	binary.LittleEndian.PutUint32(payload[pos:], indexGroup)
+	binary.LittleEndian.PutUint32(payload[pos:], spec.indexGroup)
 	pos += 4
 
 	// 4..7 IndexOffset
-	binary.LittleEndian.PutUint32(payload[pos:], indexOffset)
+	binary.LittleEndian.PutUint32(payload[pos:], spec.indexOffset)
 	pos += 4
 
 	// 8..11 Data length
-	binary.LittleEndian.PutUint32(payload[pos:], size)
+	binary.LittleEndian.PutUint32(payload[pos:], spec.size)
 	pos += 4
 
-	// 12..15 Transmission mode (3=Cyclic, 4=OnChange)
-	transmissionMode := uint32(types.ADSTransModeOnChange) // 4 = OnChange
-	if !settings.SendOnChange {
-		transmissionMode = uint32(types.ADSTransModeCyclic) // 3 = Cyclic
-	}
-	binary.LittleEndian.PutUint32(payload[pos:], transmissionMode)
+	// 12..15 Transmission mode (3=Cyclic, 4=OnChange, 5=CyclicInContext, 6=OnChangeInContext)
+	binary.LittleEndian.PutUint32(payload[pos:], uint32(spec.settings.EffectiveTransmissionMode()))
 	pos += 4
 
-	// 16..19 Maximum delay in 100ns units (milliseconds * 10000)
-	maxDelayUnits := uint32(settings.MaxDelay.Milliseconds() * 10000)
+	// 16..19 Maximum delay in 100ns units
 	binary.LittleEndian.PutUint32(payload[pos:], maxDelayUnits)
 	pos += 4
 
-	// 20..23 Cycle time in 100ns units (milliseconds * 10000)
-	cycleTimeUnits := uint32(settings.CycleTime.Milliseconds() * 10000)
+	// 20..23 Cycle time in 100ns units
 	binary.LittleEndian.PutUint32(payload[pos:], cycleTimeUnits)
 
 	// 24..39 Reserved (zeros)
 	// Already zero-initialized
+	return payload
+}
+
+// addSubscription is the internal method that sends the AddNotification command.
+func (c *Client) addSubscription(spec subscriptionSpec) (*ActiveSubscription, error) {
+	c.logger.Debug("addSubscription: Creating subscription", "port", spec.port, "indexGroup", spec.indexGroup, "indexOffset", spec.indexOffset)
+
+	if err := spec.prepare(c.serializerOptions()); err != nil {
+		return nil, fmt.Errorf("addSubscription: %w", err)
+	}
+
+	if !spec.internal {
+		c.trackPlcPort(spec.port)
+	}
+
+	if spec.settings.isPolled() {
+		return c.addPolledSubscription(spec), nil
+	}
 
 	// Send AddNotification command
 	responseData, err := c.send(AdsCommandRequest{
 		Command:    types.ADSCommandAddNotification,
-		TargetPort: port,
-		Data:       payload,
+		TargetPort: spec.port,
+		Data:       spec.addNotificationPayload(),
 	})
 	if err != nil {
 		c.logger.Error("addSubscription: Failed to send AddNotification command", "error", err)
@@ -138,20 +239,10 @@ func (c *Client) addSubscription(port uint16, indexGroup, indexOffset, size uint
 	// Parse notification handle (bytes 4-7)
 	notificationHandle := binary.LittleEndian.Uint32(responseData[4:8])
 
-	c.logger.Info("addSubscription: Subscription created", "handle", notificationHandle, "port", port)
-
-	// Create ActiveSubscription
-	sub := &ActiveSubscription{
-		Handle:   notificationHandle,
-		Port:     port,
-		Symbol:   symbol,
-		DataType: dataType,
-		Settings: settings,
-		Callback: callback,
-		IsRaw:    isRaw,
-	}
+	c.logger.Info("addSubscription: Subscription created", "handle", notificationHandle, "port", spec.port, "mode", spec.settings.EffectiveTransmissionMode().String())
 
-	// Store in subscriptions map (thread-safe)
+	// Create ActiveSubscription and store it in the subscriptions map (thread-safe)
+	sub := spec.newSubscription(notificationHandle)
 	c.subscriptionsMutex.Lock()
 	c.subscriptions[notificationHandle] = sub
 	c.subscriptionsMutex.Unlock()
@@ -165,14 +256,32 @@ func (c *Client) addSubscription(port uint16, indexGroup, indexOffset, size uint
 //
 //	err := client.Unsubscribe(sub)
 func (c *Client) Unsubscribe(sub *ActiveSubscription) error {
+	return c.unsubscribe(sub, c.send)
+}
+
+// unsubscribe deletes the notification of sub using the given send function.
+func (c *Client) unsubscribe(sub *ActiveSubscription, send func(AdsCommandRequest) ([]byte, error)) error {
 	c.logger.Debug("Unsubscribe: Unsubscribing", "handle", sub.Handle, "port", sub.Port)
 
+	if sub.clientSide {
+		// Emulated and multiplexed subscriptions have no notification of their own on the PLC
+		c.removeSubscription(sub)
+		sub.close(ErrSubscriptionClosed)
+		c.logger.Info("Unsubscribe: Client-side subscription removed", "handle", sub.Handle, "port", sub.Port)
+		if sub.multiplex != nil {
+			if err := c.leaveMultiplexGroup(sub, send); err != nil {
+				return fmt.Errorf("Unsubscribe: failed to delete shared parent notification: %w", err)
+			}
+		}
+		return nil
+	}
+
 	// Build 4-byte DeleteNotification request
 	payload := make([]byte, 4)
 	binary.LittleEndian.PutUint32(payload[0:4], sub.Handle)
 
 	// Send DeleteNotification command
-	_, err := c.send(AdsCommandRequest{
+	_, err := send(AdsCommandRequest{
 		Command:    types.ADSCommandDeleteNotification,
 		TargetPort: sub.Port,
 		Data:       payload,
@@ -183,35 +292,64 @@ func (c *Client) Unsubscribe(sub *ActiveSubscription) error {
 	}
 
 	// Remove from subscriptions map (thread-safe)
-	c.subscriptionsMutex.Lock()
-	delete(c.subscriptions, sub.Handle)
-	c.subscriptionsMutex.Unlock()
+	c.removeSubscription(sub)
+	sub.close(ErrSubscriptionClosed)
 
 	c.logger.Info("Unsubscribe: Subscription removed", "handle", sub.Handle, "port", sub.Port)
 	return nil
 }
 
 // UnsubscribeAll removes all active subscriptions.
+// Notifications are deleted with sum commands (one request per port and 500 handles).
 //
 // Example:
 //
 //	err := client.UnsubscribeAll()
 func (c *Client) UnsubscribeAll() error {
+	return c.unsubscribeAll(c.send)
+}
+
+// unsubscribeAll deletes all notifications using the given send function.
+func (c *Client) unsubscribeAll(send func(AdsCommandRequest) ([]byte, error)) error {
 	c.logger.Debug("UnsubscribeAll: Unsubscribing from all subscriptions")
 
 	// Get copy of all subscriptions (thread-safe)
-	c.subscriptionsMutex.RLock()
-	subs := make([]*ActiveSubscription, 0, len(c.subscriptions))
-	for _, sub := range c.subscriptions {
-		subs = append(subs, sub)
+	subs := c.subscriptionList()
+
+	// Client-side subscriptions are removed locally, PLC notifications are
+	// deleted with one sum command per port and 500 handles
+	var ports []uint16
+	byPort := make(map[uint16][]*ActiveSubscription)
+	errs := make(map[*ActiveSubscription]error, len(subs))
+	for _, sub := range subs {
+		if sub.clientSide {
+			errs[sub] = c.unsubscribe(sub, send)
+		}
+	}
+	for _, sub := range subs {
+		// Skip parents of multiplex groups that were deleted with their last member
+		if sub.clientSide || !c.isSubscribed(sub) {
+			continue
+		}
+		if _, ok := byPort[sub.Port]; !ok {
+			ports = append(ports, sub.Port)
+		}
+		byPort[sub.Port] = append(byPort[sub.Port], sub)
+	}
+	for _, port := range ports {
+		portSubs := byPort[port]
+		for start := 0; start < len(portSubs); start += maxSumCommandItems {
+			batch := portSubs[start:min(start+maxSumCommandItems, len(portSubs))]
+			for n, err := range c.deleteNotificationsBatch(port, batch, send) {
+				errs[batch[n]] = err
+			}
+		}
 	}
-	c.subscriptionsMutex.RUnlock()
 
-	// Unsubscribe each
 	var firstError error
 	successCount := 0
 	for _, sub := range subs {
-		if err := c.Unsubscribe(sub); err != nil {
+		if err := errs[sub]; err != nil {
 			if firstError == nil {
 				firstError = err
 			}
@@ -229,6 +367,55 @@ func (c *Client) UnsubscribeAll() error {
 	return nil
 }
 
+// subscriptionList returns a copy of all PLC and client-side subscriptions
+// of the user. Internal subscriptions of the client are not included.
+func (c *Client) subscriptionList() []*ActiveSubscription {
+	c.subscriptionsMutex.RLock()
+	defer c.subscriptionsMutex.RUnlock()
+	subs := make([]*ActiveSubscription, 0, len(c.subscriptions)+len(c.clientSubscriptions))
+	for _, sub := range c.subscriptions {
+		if sub.internal {
+			continue
+		}
+		subs = append(subs, sub)
+	}
+	for _, sub := range c.clientSubscriptions {
+		subs = append(subs, sub)
+	}
+	return subs
+}
+
+// removeSubscription removes sub from the map it is registered in and from
+// its poll or multiplex group.
+func (c *Client) removeSubscription(sub *ActiveSubscription) {
+	if sub.poll != nil {
+		c.leavePollGroup(sub)
+	}
+	if sub.multiplex != nil {
+		sub.multiplex.mu.Lock()
+		delete(sub.multiplex.members, sub)
+		sub.multiplex.mu.Unlock()
+	}
+
+	c.subscriptionsMutex.Lock()
+	defer c.subscriptionsMutex.Unlock()
+	if sub.clientSide {
+		delete(c.clientSubscriptions, sub.Handle)
+		return
+	}
+	delete(c.subscriptions, sub.Handle)
+}
+
+// isSubscribed reports whether sub is still registered.
+func (c *Client) isSubscribed(sub *ActiveSubscription) bool {
+	c.subscriptionsMutex.RLock()
+	defer c.subscriptionsMutex.RUnlock()
+	if sub.clientSide {
+		return c.clientSubscriptions[sub.Handle] == sub
+	}
+	return c.subscriptions[sub.Handle] == sub
+}
+
 // parseNotification parses the raw notification packet received from the PLC.
 // Returns a slice of notification stamps, each containing one or more samples.
 func parseNotification(data []byte) ([]notificationStamp, error) {
@@ -312,7 +499,7 @@ func filetimeToTime(filetime uint64) time.Time {
 
 // handleNotification processes received notification packets and routes them
 // to the appropriate subscription callbacks.
-func (c *Client) handleNotification(data []byte) {
+func (c *Client) handleNotification(port uint16, data []byte) {
 	c.logger.Debug("handleNotification: Processing notification packet", "dataLen", len(data))
 
 	// Parse notification packet
@@ -334,19 +521,22 @@ func (c *Client) handleNotification(data []byte) {
 
 			if sub == nil {
 				c.logger.Warn("handleNotification: Unknown notification handle", "handle", sample.Handle)
+				c.recordUnknownHandle(port, sample.Handle)
 				continue
 			}
+			c.unknownHandles[port] = 0
 
 			c.logger.Debug("handleNotification: Processing notification for subscription", "handle", sample.Handle, "port", sub.Port)
+			sub.recordNotification(time.Now())
 
-			// Process notification in a goroutine (don't block)
-			go c.processNotification(sub, sample.Payload, stamp.Timestamp)
+			// Queue for the subscription's callback (delivered in order)
+			c.enqueueNotification(sub, sample.Payload, stamp.Timestamp)
 		}
 	}
 }
 
 // processNotification parses the notification data and calls the user callback.
-// Runs in a separate goroutine to prevent blocking notification processing.
+// Runs on the subscription's queue worker to prevent blocking notification processing.
 func (c *Client) processNotification(sub *ActiveSubscription, rawData []byte, timestamp time.Time) {
 	// Recover from panics in user callback
 	defer func() {
@@ -365,11 +555,16 @@ func (c *Client) processNotification(sub *ActiveSubscription, rawData []byte, ti
 		// Parse using existing convertBufferToValue (like ReadValue does)
 		value, err = c.convertBufferToValue(rawData, *sub.DataType)
 		if err != nil {
-			c.logger.Error("processNotification: Failed to parse notification value", "error", err, "handle", sub.Handle)
+			c.reportSubscriptionError(sub, fmt.Errorf("processNotification: failed to parse notification value: %w", err))
 			return
 		}
 	}
 
+	if !sub.filter.pass(sub.Settings.Filter, value, timestamp) {
+		sub.filteredSamples.Add(1)
+		return
+	}
+
 	c.logger.Debug("processNotification: Calling user callback", "handle", sub.Handle, "timestamp", timestamp)
 
 	// Call user callback
@@ -0,0 +1,272 @@
+package ads
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	adserrors "github.com/jarmocluyse/ads-go/pkg/ads/ads-errors"
+	adsheader "github.com/jarmocluyse/ads-go/pkg/ads/ads-header"
+	adsrequests "github.com/jarmocluyse/ads-go/pkg/ads/ads-requests"
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+)
+
+// maxSumCommandItems is the maximum number of sub-commands TwinCAT accepts in one sum command.
+const maxSumCommandItems = 500
+
+// SubscriptionRequest describes one subscription for SubscribeMany.
+type SubscriptionRequest struct {
+	// Port is the target ADS port.
+	Port uint16
+
+	// Path is the symbol path. The value is parsed like SubscribeValue.
+	// If empty, IndexGroup, IndexOffset and Size are used like SubscribeRaw.
+	Path string
+
+	// IndexGroup, IndexOffset and Size address the raw data when Path is empty.
+	IndexGroup  uint32
+	IndexOffset uint32
+	Size        uint32
+
+	// Callback is called for every received value.
+	Callback SubscriptionCallback
+
+	// Settings are the subscription settings.
+	Settings SubscriptionSettings
+}
+
+// SubscriptionResult is the outcome of one SubscriptionRequest.
+// Exactly one of Subscription and Err is set.
+type SubscriptionResult struct {
+	Subscription *ActiveSubscription
+	Err          error
+}
+
+// SubscribeMany creates many subscriptions at once. The AddNotification
+// requests are combined into sum commands (up to 500 per request and port),
+// so subscribing to hundreds of variables takes a few round trips instead of
+// one per variable. Symbols of path requests are still resolved one by one.
+//
+// The results have the same order as requests. A failing item does not affect
+// the others; the returned error is non-nil if at least one item failed and
+// wraps the first failure.
+//
+// Example:
+//
+//	results, err := client.SubscribeMany([]ads.SubscriptionRequest{
+//	    {Port: 851, Path: "GVL.Counter", Callback: onCounter, Settings: settings},
+//	    {Port: 851, Path: "GVL.Status", Callback: onStatus, Settings: settings},
+//	})
+//	for i, res := range results {
+//	    if res.Err != nil {
+//	        log.Printf("request %d failed: %v", i, res.Err)
+//	    }
+//	}
+func (c *Client) SubscribeMany(requests []SubscriptionRequest) ([]SubscriptionResult, error) {
+	c.logger.Debug("SubscribeMany: Subscribing", "count", len(requests))
+	results := make([]SubscriptionResult, len(requests))
+
+	// Resolve and validate every request, PLC subscriptions are grouped by port
+	specs := make([]subscriptionSpec, len(requests))
+	byPort := make(map[uint16][]int)
+	var ports []uint16
+	for i, req := range requests {
+		spec, err := c.subscriptionSpecFor(req)
+		if err == nil {
+			err = spec.prepare(c.serializerOptions())
+		}
+		if err != nil {
+			results[i].Err = fmt.Errorf("SubscribeMany: %w", err)
+			continue
+		}
+		if spec.settings.isPolled() {
+			results[i].Subscription = c.addPolledSubscription(spec)
+			continue
+		}
+		specs[i] = spec
+		if _, ok := byPort[spec.port]; !ok {
+			ports = append(ports, spec.port)
+		}
+		byPort[spec.port] = append(byPort[spec.port], i)
+	}
+
+	for _, port := range ports {
+		indices := byPort[port]
+		for start := 0; start < len(indices); start += maxSumCommandItems {
+			end := min(start+maxSumCommandItems, len(indices))
+			c.addSubscriptionsBatch(port, specs, indices[start:end], results)
+		}
+	}
+
+	failed := 0
+	var firstErr error
+	for _, res := range results {
+		if res.Err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = res.Err
+			}
+		}
+	}
+	c.logger.Info("SubscribeMany: Subscriptions created", "total", len(requests), "failed", failed)
+	if firstErr != nil {
+		return results, fmt.Errorf("SubscribeMany: %d of %d subscriptions failed: %w", failed, len(requests), firstErr)
+	}
+	return results, nil
+}
+
+// subscriptionSpecFor resolves the symbol and data type of a path request.
+func (c *Client) subscriptionSpecFor(req SubscriptionRequest) (subscriptionSpec, error) {
+	spec := subscriptionSpec{
+		port:        req.Port,
+		indexGroup:  req.IndexGroup,
+		indexOffset: req.IndexOffset,
+		size:        req.Size,
+		callback:    req.Callback,
+		settings:    req.Settings,
+		isRaw:       req.Path == "",
+	}
+	if req.Callback == nil {
+		return spec, fmt.Errorf("missing callback")
+	}
+	if spec.isRaw {
+		return spec, nil
+	}
+
+	symbol, err := c.GetSymbol(req.Port, req.Path)
+	if err != nil {
+		return spec, fmt.Errorf("failed to get symbol %q: %w", req.Path, err)
+	}
+	dataType, err := c.GetDataType(symbol.Type, req.Port)
+	if err != nil {
+		return spec, fmt.Errorf("failed to get data type of %q: %w", req.Path, err)
+	}
+	spec.indexGroup = symbol.IndexGroup
+	spec.indexOffset = symbol.IndexOffset
+	spec.size = symbol.Size
+	spec.symbol = symbol
+	spec.dataType = &dataType
+	return spec, nil
+}
+
+// addSubscriptionsBatch creates the subscriptions specs[i] for all i in
+// indices with one AddDevNote sum command and stores the outcome in results.
+// If the target does not support or rejects the sum command, the items are
+// created one by one.
+func (c *Client) addSubscriptionsBatch(port uint16, specs []subscriptionSpec, indices []int, results []SubscriptionResult) {
+	writeData := make([]byte, 0, 40*len(indices))
+	for _, i := range indices {
+		writeData = append(writeData, specs[i].addNotificationPayload()...)
+	}
+
+	// Response: per item [0..3] error code, [4..7] notification handle
+	oneByOne := !c.sumCommandsSupported()
+	var data []byte
+	var err error
+	if !oneByOne {
+		data, err = c.sumCommand(port, types.ADSReservedIndexGroupSumCommandAddDevNote, len(indices), 8*len(indices), writeData, c.send)
+		if errors.Is(err, adserrors.ErrAdsError) {
+			c.logger.Warn("SubscribeMany: Sum command rejected, subscribing one by one", "port", port, "error", err)
+			oneByOne = true
+		}
+	}
+	if oneByOne {
+		for _, i := range indices {
+			sub, err := c.addSubscription(specs[i])
+			results[i] = SubscriptionResult{Subscription: sub, Err: err}
+		}
+		return
+	}
+	if err != nil {
+		for _, i := range indices {
+			results[i].Err = fmt.Errorf("SubscribeMany: %w", err)
+		}
+		return
+	}
+
+	track := false
+	c.subscriptionsMutex.Lock()
+	for n, i := range indices {
+		item := data[n*8 : n*8+8]
+		if err := adserrors.CheckAdsError(item[0:4]); err != nil {
+			results[i].Err = fmt.Errorf("SubscribeMany: AddNotification failed: %w", err)
+			continue
+		}
+		sub := specs[i].newSubscription(binary.LittleEndian.Uint32(item[4:8]))
+		c.subscriptions[sub.Handle] = sub
+		results[i].Subscription = sub
+		track = track || !specs[i].internal
+	}
+	c.subscriptionsMutex.Unlock()
+
+	// Like addSubscription; outside the lock, tracking may subscribe to the state
+	if track {
+		c.trackPlcPort(port)
+	}
+}
+
+// deleteNotificationsBatch deletes the notifications of subs (all on port)
+// with one DelDevNote sum command. It returns one error per subscription.
+func (c *Client) deleteNotificationsBatch(port uint16, subs []*ActiveSubscription, send func(AdsCommandRequest) ([]byte, error)) []error {
+	errs := make([]error, len(subs))
+	writeData := make([]byte, 4*len(subs))
+	for n, sub := range subs {
+		binary.LittleEndian.PutUint32(writeData[n*4:], sub.Handle)
+	}
+
+	// Response: per item [0..3] error code
+	oneByOne := !c.sumCommandsSupported()
+	var data []byte
+	var err error
+	if !oneByOne {
+		data, err = c.sumCommand(port, types.ADSReservedIndexGroupSumCommandDelDevNote, len(subs), 4*len(subs), writeData, send)
+		if errors.Is(err, adserrors.ErrAdsError) {
+			c.logger.Warn("UnsubscribeAll: Sum command rejected, unsubscribing one by one", "port", port, "error", err)
+			oneByOne = true
+		}
+	}
+	if oneByOne {
+		for n, sub := range subs {
+			errs[n] = c.unsubscribe(sub, send)
+		}
+		return errs
+	}
+	if err != nil {
+		for n := range subs {
+			errs[n] = fmt.Errorf("UnsubscribeAll: failed to send DeleteNotification sum command: %w", err)
+		}
+		return errs
+	}
+
+	for n, sub := range subs {
+		if err := adserrors.CheckAdsError(data[n*4 : n*4+4]); err != nil {
+			errs[n] = fmt.Errorf("UnsubscribeAll: DeleteNotification failed for handle %d: %w", sub.Handle, err)
+			continue
+		}
+		c.removeSubscription(sub)
+		sub.close(ErrSubscriptionClosed)
+	}
+	return errs
+}
+
+// sumCommand sends a sum command with count sub-commands and returns the
+// response data, which must hold at least readLength bytes.
+func (c *Client) sumCommand(port uint16, indexGroup types.ADSReservedIndexGroup, count, readLength int, writeData []byte, send func(AdsCommandRequest) ([]byte, error)) ([]byte, error) {
+	c.logger.Debug("sumCommand: Sending sum command", "indexGroup", indexGroup.ADSReservedIndexGroupToString(), "count", count)
+	response, err := send(AdsCommandRequest{
+		Command:    types.ADSCommandReadWrite,
+		TargetPort: port,
+		Data:       adsrequests.BuildReadWriteRequest(uint32(indexGroup), uint32(count), uint32(readLength), writeData),
+	})
+	if err != nil {
+		return nil, err
+	}
+	data, err := adsheader.StripAdsHeader(response)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < readLength {
+		return nil, fmt.Errorf("sumCommand: invalid response length: %d bytes (expected %d)", len(data), readLength)
+	}
+	return data, nil
+}
@@ -0,0 +1,111 @@
+package ads
+
+import (
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSubscribeManyPartialFailure verifies that SubscribeMany sends one sum
+// command and reports failures per item.
+func TestSubscribeManyPartialFailure(t *testing.T) {
+	var sumRequests, deleted atomic.Int32
+	router := newFakeRouter(t, func(cmd types.ADSCommand, port uint16, data []byte) []byte {
+		if cmd != types.ADSCommandReadWrite {
+			return defaultFakeHandler(cmd, port, data)
+		}
+		indexGroup, count := sumCommandHeader(data)
+		switch indexGroup {
+		case types.ADSReservedIndexGroupSumCommandAddDevNote:
+			sumRequests.Add(1)
+			resp := make([]byte, 8+8*count)
+			binary.LittleEndian.PutUint32(resp[4:8], uint32(8*count))
+			for n := range count {
+				binary.LittleEndian.PutUint32(resp[8+n*8+4:], uint32(10+n))
+			}
+			binary.LittleEndian.PutUint32(resp[8+8:], 1808) // second item: symbol not found
+			return resp
+		case types.ADSReservedIndexGroupSumCommandDelDevNote:
+			deleted.Add(int32(count))
+		}
+		return defaultFakeHandler(cmd, port, data)
+	})
+	c := NewClient(router.settings(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer func() { _ = c.Disconnect() }()
+
+	callback := func(SubscriptionData) {}
+	results, err := c.SubscribeMany([]SubscriptionRequest{
+		{Port: 851, IndexGroup: 0x4020, IndexOffset: 0, Size: 4, Callback: callback},
+		{Port: 851, IndexGroup: 0x4020, IndexOffset: 4, Size: 4, Callback: callback},
+		{Port: 851, IndexGroup: 0x4020, IndexOffset: 8, Size: 4, Callback: callback, Settings: SubscriptionSettings{CycleTime: 50 * time.Nanosecond}},
+		{Port: 851, IndexGroup: 0x4020, IndexOffset: 12, Size: 4, Callback: callback},
+	})
+	assert.Error(t, err)
+	if len(results) != 4 {
+		t.Fatalf("Expected 4 results, got %d", len(results))
+	}
+
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, uint32(10), results[0].Subscription.Handle)
+	assert.Error(t, results[1].Err)
+	assert.Nil(t, results[1].Subscription)
+	assert.Error(t, results[2].Err, "invalid settings are reported per item")
+	assert.NoError(t, results[3].Err)
+	assert.Equal(t, uint32(12), results[3].Subscription.Handle)
+	assert.Equal(t, int32(1), sumRequests.Load())
+	assert.Len(t, c.Debug().Subscriptions, 2)
+	assert.Equal(t, []uint16{851}, c.trackedPlcPorts(), "the PLC runtime state of the port is tracked")
+
+	if err := c.UnsubscribeAll(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, int32(2), deleted.Load())
+	assert.Empty(t, c.Debug().Subscriptions)
+}
+
+// TestSubscribeManyFallback verifies that targets without sum command
+// support get one AddNotification per item.
+func TestSubscribeManyFallback(t *testing.T) {
+	var adds atomic.Int32
+	router := newFakeRouter(t, func(cmd types.ADSCommand, port uint16, data []byte) []byte {
+		switch cmd {
+		case types.ADSCommandReadWrite:
+			if indexGroup, _ := sumCommandHeader(data); indexGroup == types.ADSReservedIndexGroupSumCommandAddDevNote {
+				resp := make([]byte, 8)
+				binary.LittleEndian.PutUint32(resp[0:4], 1793) // service not supported
+				return resp
+			}
+		case types.ADSCommandAddNotification:
+			resp := make([]byte, 8)
+			binary.LittleEndian.PutUint32(resp[4:8], uint32(adds.Add(1)))
+			return resp
+		}
+		return defaultFakeHandler(cmd, port, data)
+	})
+	c := NewClient(router.settings(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer func() { _ = c.Disconnect() }()
+
+	callback := func(SubscriptionData) {}
+	results, err := c.SubscribeMany([]SubscriptionRequest{
+		{Port: 851, IndexGroup: 0x4020, IndexOffset: 0, Size: 4, Callback: callback},
+		{Port: 851, IndexGroup: 0x4020, IndexOffset: 4, Size: 4, Callback: callback},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, int32(2), adds.Load())
+	assert.Equal(t, uint32(1), results[0].Subscription.Handle)
+	assert.Equal(t, uint32(2), results[1].Subscription.Handle)
+}
@@ -0,0 +1,121 @@
+package ads
+
+import (
+	"context"
+	"sync"
+)
+
+// SubscribeValueChan subscribes to a variable by path like SubscribeValue, but
+// delivers the values on a channel instead of calling a callback.
+//
+// The channel is closed when ctx is cancelled, the subscription is removed
+// (Unsubscribe, UnsubscribeAll, Disconnect, Close) or the connection is lost.
+// The last value before the close carries the reason in Err: ctx.Err(),
+// ErrSubscriptionClosed, ErrClientClosed or an error wrapping ErrNotConnected.
+// When ctx is cancelled the notification is deleted on the PLC as well.
+//
+// Values are queued per SubscriptionSettings.QueueSize and OverflowPolicy
+// while the consumer is not reading.
+//
+// Example:
+//
+//	values, _, err := client.SubscribeValueChan(ctx, 851, "GVL.Counter",
+//	    ads.SubscriptionSettings{CycleTime: 100 * time.Millisecond, SendOnChange: true})
+//	if err != nil {
+//	    return err
+//	}
+//	for data := range values {
+//	    if data.Err != nil {
+//	        return data.Err
+//	    }
+//	    fmt.Println(data.Value)
+//	}
+func (c *Client) SubscribeValueChan(ctx context.Context, port uint16, path string, settings SubscriptionSettings) (<-chan SubscriptionData, *ActiveSubscription, error) {
+	return c.subscribeChan(ctx, func(callback SubscriptionCallback, onTerminate func(err error)) (*ActiveSubscription, error) {
+		return c.subscribeValue(port, path, callback, settings, onTerminate)
+	})
+}
+
+// subscribeChan adapts a callback subscription to a channel.
+// subscribe must create the subscription with the given callback and onTerminate.
+func (c *Client) subscribeChan(ctx context.Context, subscribe func(callback SubscriptionCallback, onTerminate func(err error)) (*ActiveSubscription, error)) (<-chan SubscriptionData, *ActiveSubscription, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	out := make(chan SubscriptionData)
+	in := make(chan SubscriptionData)
+	done := make(chan struct{})
+	var endErr error
+	var endOnce sync.Once
+	end := func(err error) {
+		endOnce.Do(func() {
+			endErr = err
+			close(done)
+		})
+	}
+
+	callback := func(data SubscriptionData) {
+		select {
+		case in <- data:
+		case <-done:
+		}
+	}
+
+	sub, err := subscribe(callback, end)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The forwarder owns out, so it is never closed while a value is being sent.
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case data := <-in:
+				select {
+				case out <- data:
+				case <-done:
+					c.sendTerminalValue(ctx, out, endErr)
+					return
+				}
+			case <-done:
+				c.sendTerminalValue(ctx, out, endErr)
+				return
+			}
+		}
+	}()
+
+	// Delete the notification when the consumer goes away.
+	go func() {
+		select {
+		case <-ctx.Done():
+			sub.terminate(ctx.Err())
+			if err := c.Unsubscribe(sub); err != nil {
+				c.logger.Warn("SubscribeValueChan: Failed to unsubscribe after context cancellation", "handle", sub.Handle, "error", err)
+				// Drop the local registration anyway, the channel is already closed.
+				c.removeSubscription(sub)
+				sub.closeQueue()
+			}
+		case <-done:
+		}
+	}()
+
+	return out, sub, nil
+}
+
+// sendTerminalValue delivers the final value carrying err. It gives up when
+// ctx is done and the consumer is not reading anymore.
+func (c *Client) sendTerminalValue(ctx context.Context, out chan<- SubscriptionData, err error) {
+	terminal := SubscriptionData{Err: err}
+	select {
+	case out <- terminal:
+		return
+	default:
+	}
+	select {
+	case out <- terminal:
+	case <-ctx.Done():
+		c.logger.Debug("SubscribeValueChan: Consumer gone, terminal value not delivered", "error", err)
+	}
+}
@@ -0,0 +1,139 @@
+package ads
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// subscribeRawChan subscribes to a raw address through the channel adapter
+// used by SubscribeValueChan, so no symbol lookup is needed.
+func subscribeRawChan(ctx context.Context, c *Client) (<-chan SubscriptionData, *ActiveSubscription, error) {
+	return c.subscribeChan(ctx, func(callback SubscriptionCallback, onTerminate func(err error)) (*ActiveSubscription, error) {
+		return c.addSubscription(subscriptionSpec{
+			port: 851, indexGroup: 0x4020, size: 4,
+			callback: callback, isRaw: true, onTerminate: onTerminate,
+		})
+	})
+}
+
+// connectChanTestClient connects a client to a fake router that answers
+// AddNotification with handle 1 and counts DeleteNotification requests.
+func connectChanTestClient(t *testing.T, deletes *atomic.Int32) (*Client, *fakeRouter) {
+	router := newFakeRouter(t, func(cmd types.ADSCommand, port uint16, data []byte) []byte {
+		switch cmd {
+		case types.ADSCommandAddNotification:
+			resp := make([]byte, 8)
+			binary.LittleEndian.PutUint32(resp[4:8], 1)
+			return resp
+		case types.ADSCommandDeleteNotification:
+			deletes.Add(1)
+		}
+		return defaultFakeHandler(cmd, port, data)
+	})
+	c := NewClient(router.settings(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return c, router
+}
+
+// receive reads one value from ch or fails the test.
+func receive(t *testing.T, ch <-chan SubscriptionData) SubscriptionData {
+	t.Helper()
+	select {
+	case data, ok := <-ch:
+		if !ok {
+			t.Fatalf("Expected a value, channel was closed")
+		}
+		return data
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting for a value")
+	}
+	return SubscriptionData{}
+}
+
+// expectClosed verifies that ch is closed after its terminal value.
+func expectClosed(t *testing.T, ch <-chan SubscriptionData) {
+	t.Helper()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Fatalf("Expected channel to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Timed out waiting for channel close")
+	}
+}
+
+// TestSubscribeChanContextCancel verifies value delivery and the terminal
+// value after context cancellation.
+func TestSubscribeChanContextCancel(t *testing.T) {
+	var deletes atomic.Int32
+	c, router := connectChanTestClient(t, &deletes)
+	defer func() { _ = c.Disconnect() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	values, _, err := subscribeRawChan(ctx, c)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	router.notify(851, 1, []byte{42, 0, 0, 0})
+	data := receive(t, values)
+	assert.NoError(t, data.Err)
+	assert.Equal(t, []byte{42, 0, 0, 0}, data.RawValue)
+
+	cancel()
+	data = receive(t, values)
+	assert.True(t, errors.Is(data.Err, context.Canceled), "expected context.Canceled, got %v", data.Err)
+	expectClosed(t, values)
+
+	waitFor(t, time.Second, func() bool { return deletes.Load() == 1 }, "DeleteNotification")
+	assert.Empty(t, c.Debug().Subscriptions)
+}
+
+// TestSubscribeChanUnsubscribe verifies the terminal value after Unsubscribe.
+func TestSubscribeChanUnsubscribe(t *testing.T) {
+	var deletes atomic.Int32
+	c, _ := connectChanTestClient(t, &deletes)
+	defer func() { _ = c.Disconnect() }()
+
+	values, sub, err := subscribeRawChan(context.Background(), c)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := c.Unsubscribe(sub); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data := receive(t, values)
+	assert.True(t, errors.Is(data.Err, ErrSubscriptionClosed), "expected ErrSubscriptionClosed, got %v", data.Err)
+	expectClosed(t, values)
+}
+
+// TestSubscribeChanConnectionLost verifies the terminal value after a
+// connection loss.
+func TestSubscribeChanConnectionLost(t *testing.T) {
+	var deletes atomic.Int32
+	c, router := connectChanTestClient(t, &deletes)
+
+	values, _, err := subscribeRawChan(context.Background(), c)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	router.dropConnection()
+
+	data := receive(t, values)
+	assert.True(t, errors.Is(data.Err, ErrNotConnected), "expected ErrNotConnected, got %v", data.Err)
+	expectClosed(t, values)
+	assert.Empty(t, c.Debug().Subscriptions)
+}
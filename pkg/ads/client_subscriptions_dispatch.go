@@ -0,0 +1,130 @@
+package ads
+
+import (
+	"sync"
+	"time"
+)
+
+// notificationQueue delivers the samples of one subscription to its callback
+// in the order they were received. A worker goroutine is started when the
+// first sample is queued and exits when the queue runs empty, so idle
+// subscriptions do not hold a goroutine.
+type notificationQueue struct {
+	mu      sync.Mutex
+	space   *sync.Cond           // signalled when a sample is taken or the queue is closed (OverflowBlock)
+	items   []queuedNotification // pending samples, oldest first
+	running bool                 // a worker goroutine is draining items
+	closed  bool                 // the subscription was removed, new samples are discarded
+}
+
+// queuedNotification is a sample waiting for its callback.
+type queuedNotification struct {
+	payload   []byte
+	timestamp time.Time
+}
+
+// enqueueNotification queues a sample for the callback of sub and applies the
+// overflow policy when the queue is full.
+func (c *Client) enqueueNotification(sub *ActiveSubscription, payload []byte, timestamp time.Time) {
+	q := &sub.queue
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return
+	}
+
+	limit := sub.Settings.QueueSize
+	if sub.Settings.OverflowPolicy == OverflowKeepLatest || limit <= 0 {
+		limit = 1
+	}
+
+	for len(q.items) >= limit {
+		switch sub.Settings.OverflowPolicy {
+		case OverflowBlock:
+			if q.space == nil {
+				q.space = sync.NewCond(&q.mu)
+			}
+			q.space.Wait()
+			if q.closed {
+				return
+			}
+			continue
+		default: // OverflowDropOldest, OverflowKeepLatest
+			q.items[0] = queuedNotification{}
+			q.items = q.items[1:]
+			sub.droppedSamples.Add(1)
+			c.logger.Debug("enqueueNotification: Queue full, dropped oldest sample", "handle", sub.Handle, "policy", sub.Settings.OverflowPolicy.String())
+		}
+	}
+	q.items = append(q.items, queuedNotification{payload: payload, timestamp: timestamp})
+
+	if q.running {
+		return
+	}
+	if !c.beginCallback() {
+		c.logger.Debug("enqueueNotification: Client is closing, discarding sample", "handle", sub.Handle)
+		q.items = nil
+		return
+	}
+	q.running = true
+	go c.drainNotifications(sub)
+}
+
+// drainNotifications calls the callback of sub for every queued sample until
+// the queue is empty.
+func (c *Client) drainNotifications(sub *ActiveSubscription) {
+	defer c.callbacks.Done()
+	q := &sub.queue
+	for {
+		q.mu.Lock()
+		if len(q.items) == 0 || q.closed {
+			q.items = nil
+			q.running = false
+			q.mu.Unlock()
+			return
+		}
+		item := q.items[0]
+		q.items[0] = queuedNotification{}
+		q.items = q.items[1:]
+		if q.space != nil {
+			q.space.Signal()
+		}
+		q.mu.Unlock()
+
+		c.processNotification(sub, item.payload, item.timestamp)
+	}
+}
+
+// close ends the subscription locally: it is marked dead, pending samples
+// are discarded, new ones are ignored and onTerminate is called with err. A callback that is
+// already running is not interrupted.
+func (sub *ActiveSubscription) close(err error) {
+	sub.state.Store(int32(SubscriptionStateDead))
+	sub.closeQueue()
+	sub.terminate(err)
+}
+
+// terminate calls onTerminate with err. Only the first call has an effect.
+func (sub *ActiveSubscription) terminate(err error) {
+	sub.terminateOnce.Do(func() {
+		if sub.onTerminate != nil {
+			sub.onTerminate(err)
+		}
+	})
+}
+
+// closeQueue discards pending samples of sub and stops accepting new ones.
+func (sub *ActiveSubscription) closeQueue() {
+	q := &sub.queue
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return
+	}
+	q.closed = true
+	q.items = nil
+	if q.space != nil {
+		q.space.Broadcast()
+	}
+}
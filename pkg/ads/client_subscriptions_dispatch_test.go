@@ -0,0 +1,149 @@
+package ads
+
+import (
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingSubscription returns a raw subscription whose callback records the
+// first payload byte. While gate is non-nil, each callback waits on it.
+func recordingSubscription(settings SubscriptionSettings, gate chan struct{}) (*ActiveSubscription, func() []byte, chan struct{}) {
+	var mu sync.Mutex
+	var received []byte
+	started := make(chan struct{}, 16)
+	sub := &ActiveSubscription{Handle: 1, Port: 851, IsRaw: true, Settings: settings}
+	sub.Callback = func(data SubscriptionData) {
+		started <- struct{}{}
+		if gate != nil {
+			<-gate
+		}
+		mu.Lock()
+		received = append(received, data.RawValue[0])
+		mu.Unlock()
+	}
+	return sub, func() []byte {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]byte(nil), received...)
+	}, started
+}
+
+// TestNotificationOrder verifies that samples reach the callback in the order
+// they were received from the router.
+func TestNotificationOrder(t *testing.T) {
+	router := newFakeRouter(t, func(cmd types.ADSCommand, port uint16, data []byte) []byte {
+		if cmd == types.ADSCommandAddNotification {
+			resp := make([]byte, 8)
+			binary.LittleEndian.PutUint32(resp[4:8], 1)
+			return resp
+		}
+		return defaultFakeHandler(cmd, port, data)
+	})
+	c := NewClient(router.settings(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer func() { _ = c.Disconnect() }()
+
+	var mu sync.Mutex
+	var received []uint32
+	_, err := c.SubscribeRaw(851, 0x4020, 0, 4, func(data SubscriptionData) {
+		mu.Lock()
+		received = append(received, binary.LittleEndian.Uint32(data.RawValue))
+		mu.Unlock()
+	}, SubscriptionSettings{QueueSize: 1000})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	const count = 200
+	for i := uint32(0); i < count; i++ {
+		value := make([]byte, 4)
+		binary.LittleEndian.PutUint32(value, i)
+		router.notify(851, 1, value)
+	}
+
+	waitFor(t, 2*time.Second, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(received) == count
+	}, "all samples")
+	mu.Lock()
+	defer mu.Unlock()
+	for i, value := range received {
+		if value != uint32(i) {
+			t.Fatalf("Expected sample %d at position %d, got %d", i, i, value)
+		}
+	}
+}
+
+// TestNotificationOverflowPolicies verifies the queue behavior when the
+// callback cannot keep up.
+func TestNotificationOverflowPolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings SubscriptionSettings
+		expected []byte
+		dropped  uint64
+	}{
+		{"drop oldest", SubscriptionSettings{QueueSize: 2, OverflowPolicy: OverflowDropOldest}, []byte{0, 4, 5}, 3},
+		{"keep latest", SubscriptionSettings{QueueSize: 10, OverflowPolicy: OverflowKeepLatest}, []byte{0, 5}, 4},
+		{"block", SubscriptionSettings{QueueSize: 2, OverflowPolicy: OverflowBlock}, []byte{0, 1, 2, 3, 4, 5}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(ClientSettings{})
+			gate := make(chan struct{})
+			sub, received, started := recordingSubscription(tt.settings, gate)
+
+			// The first sample occupies the callback, the rest has to queue.
+			c.enqueueNotification(sub, []byte{0}, time.Now())
+			<-started
+
+			enqueued := make(chan struct{})
+			go func() {
+				for i := byte(1); i <= 5; i++ {
+					c.enqueueNotification(sub, []byte{i}, time.Now())
+				}
+				close(enqueued)
+			}()
+			if tt.settings.OverflowPolicy != OverflowBlock {
+				<-enqueued
+			}
+
+			close(gate)
+			<-enqueued
+			waitFor(t, time.Second, func() bool { return len(received()) == len(tt.expected) }, "callbacks")
+			c.callbacks.Wait()
+
+			assert.Equal(t, tt.expected, received())
+			assert.Equal(t, tt.dropped, sub.DroppedSamples())
+		})
+	}
+}
+
+// TestNotificationQueueClosed verifies that queued samples are discarded when
+// the subscription is removed.
+func TestNotificationQueueClosed(t *testing.T) {
+	c := newTestClient(ClientSettings{})
+	gate := make(chan struct{})
+	sub, received, started := recordingSubscription(SubscriptionSettings{QueueSize: 10}, gate)
+
+	c.enqueueNotification(sub, []byte{0}, time.Now())
+	<-started
+	c.enqueueNotification(sub, []byte{1}, time.Now())
+	sub.closeQueue()
+	c.enqueueNotification(sub, []byte{2}, time.Now())
+
+	close(gate)
+	c.callbacks.Wait()
+	assert.Equal(t, []byte{0}, received())
+}
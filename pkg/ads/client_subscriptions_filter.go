@@ -0,0 +1,205 @@
+package ads
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	adsserializer "github.com/jarmocluyse/ads-go/pkg/ads/ads-serializer"
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+)
+
+// DeadbandType selects how SubscriptionFilter.Deadband is interpreted.
+type DeadbandType int
+
+const (
+	// DeadbandNone disables the deadband.
+	DeadbandNone DeadbandType = iota
+	// DeadbandAbsolute suppresses values that differ from the last delivered
+	// value by Deadband or less (in the unit of the variable).
+	DeadbandAbsolute
+	// DeadbandPercent suppresses values that differ from the last delivered
+	// value by Deadband percent of the range RangeHigh - RangeLow or less.
+	DeadbandPercent
+)
+
+// String returns the string representation of the deadband type.
+func (d DeadbandType) String() string {
+	switch d {
+	case DeadbandNone:
+		return "None"
+	case DeadbandAbsolute:
+		return "Absolute"
+	case DeadbandPercent:
+		return "Percent"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// SubscriptionFilter suppresses samples before the callback is called. The
+// zero value disables filtering. Deadband and Hysteresis follow the OPC UA
+// data change filter semantics and require a numeric variable subscribed with
+// SubscribeValue; MinInterval works for every subscription.
+//
+// The first sample is always delivered. Later samples are compared with the
+// last delivered value, not with the previous sample, so slow drifts are
+// still reported once they exceed the deadband.
+type SubscriptionFilter struct {
+	// DeadbandType selects the deadband (default: DeadbandNone).
+	DeadbandType DeadbandType
+
+	// Deadband is the change that is suppressed: an absolute value for
+	// DeadbandAbsolute or a percentage (0-100) of the range for DeadbandPercent.
+	// A sample is delivered if |value - last delivered value| > Deadband.
+	Deadband float64
+
+	// RangeLow and RangeHigh are the engineering unit range of the variable
+	// (OPC UA EURange). Required for DeadbandPercent.
+	RangeLow  float64
+	RangeHigh float64
+
+	// Hysteresis is the additional change required when the value reverses
+	// direction compared to the last delivered change (default: 0 = off).
+	// It suppresses a noisy signal that oscillates around a level.
+	Hysteresis float64
+
+	// MinInterval is the minimum time between two delivered samples, based on
+	// the sample timestamps (default: 0 = off). Samples within MinInterval
+	// after the last delivered sample are dropped.
+	MinInterval time.Duration
+}
+
+// usesValue reports whether the filter needs the numeric value of a sample.
+func (f SubscriptionFilter) usesValue() bool {
+	return f.DeadbandType != DeadbandNone || f.Hysteresis > 0
+}
+
+// validate checks the filter settings.
+func (f SubscriptionFilter) validate() error {
+	switch {
+	case f.DeadbandType < DeadbandNone || f.DeadbandType > DeadbandPercent:
+		return fmt.Errorf("invalid DeadbandType %d", f.DeadbandType)
+	case f.Deadband < 0 || math.IsNaN(f.Deadband):
+		return fmt.Errorf("invalid Deadband %v: must not be negative", f.Deadband)
+	case f.DeadbandType == DeadbandPercent && f.Deadband > 100:
+		return fmt.Errorf("invalid Deadband %v: percent must be at most 100", f.Deadband)
+	case f.DeadbandType == DeadbandPercent && !(f.RangeHigh > f.RangeLow):
+		return fmt.Errorf("invalid range [%v, %v]: DeadbandPercent requires RangeHigh > RangeLow", f.RangeLow, f.RangeHigh)
+	case f.Hysteresis < 0 || math.IsNaN(f.Hysteresis):
+		return fmt.Errorf("invalid Hysteresis %v: must not be negative", f.Hysteresis)
+	case f.MinInterval < 0:
+		return fmt.Errorf("invalid MinInterval %s: must not be negative", f.MinInterval)
+	}
+	return nil
+}
+
+// threshold returns the deadband in the unit of the variable.
+func (f SubscriptionFilter) threshold() float64 {
+	switch f.DeadbandType {
+	case DeadbandAbsolute:
+		return f.Deadband
+	case DeadbandPercent:
+		return f.Deadband / 100 * (f.RangeHigh - f.RangeLow)
+	default:
+		return 0
+	}
+}
+
+// filterState is the filter state of one subscription. It is only accessed
+// by the notification worker, which handles one sample at a time.
+type filterState struct {
+	started   bool      // a sample was delivered
+	last      float64   // last delivered numeric value
+	direction int       // sign of the last delivered change (-1, 0, 1)
+	lastTime  time.Time // timestamp of the last delivered sample
+}
+
+// pass reports whether a sample passes filter and records it if so.
+func (s *filterState) pass(filter SubscriptionFilter, value any, timestamp time.Time) bool {
+	numeric, isNumeric := numericValue(value)
+	if !s.started {
+		s.started = true
+		s.last, s.lastTime = numeric, timestamp
+		return true
+	}
+
+	if filter.MinInterval > 0 && timestamp.Sub(s.lastTime) < filter.MinInterval {
+		return false
+	}
+
+	direction := s.direction
+	if filter.usesValue() && isNumeric {
+		diff := numeric - s.last
+		threshold := filter.threshold()
+		direction = sign(diff)
+		if s.direction != 0 && direction != 0 && direction != s.direction {
+			threshold += filter.Hysteresis
+		}
+		if math.Abs(diff) <= threshold {
+			return false
+		}
+	}
+
+	s.last, s.lastTime, s.direction = numeric, timestamp, direction
+	return true
+}
+
+// sign returns -1, 0 or 1 for the sign of v.
+func sign(v float64) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+// numericValue converts a deserialized scalar to float64.
+func numericValue(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int8:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// isNumericDataType reports whether dataType is a numeric scalar that a
+// deadband can be applied to when converted with opts.
+func isNumericDataType(dataType types.AdsDataType, opts adsserializer.Options) bool {
+	if len(dataType.SubItems) > 0 || len(dataType.ArrayInfo) > 0 || len(dataType.EnumInfo) > 0 {
+		return false // enums have no distance between their values
+	}
+	if opts.LookupTimeType(dataType.Type) != adsserializer.TimeTypeNone {
+		return false // parsed as time.Duration or time.Time
+	}
+	switch dataType.DataType {
+	case types.ADST_INT8, types.ADST_UINT8, types.ADST_INT16, types.ADST_UINT16,
+		types.ADST_INT32, types.ADST_UINT32, types.ADST_INT64, types.ADST_UINT64,
+		types.ADST_REAL32, types.ADST_REAL64:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,98 @@
+package ads
+
+import (
+	"testing"
+	"time"
+
+	adsserializer "github.com/jarmocluyse/ads-go/pkg/ads/ads-serializer"
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// applyFilter runs values through filter with timestamps 100ms apart and
+// returns the delivered values.
+func applyFilter(filter SubscriptionFilter, values ...any) []any {
+	var state filterState
+	var delivered []any
+	start := time.Now()
+	for i, value := range values {
+		if state.pass(filter, value, start.Add(time.Duration(i)*100*time.Millisecond)) {
+			delivered = append(delivered, value)
+		}
+	}
+	return delivered
+}
+
+func TestSubscriptionFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter SubscriptionFilter
+		values []any
+		want   []any
+	}{
+		{
+			name:   "no filter",
+			values: []any{float32(1), float32(1), float32(2)},
+			want:   []any{float32(1), float32(1), float32(2)},
+		},
+		{
+			name:   "absolute deadband compares with last delivered value",
+			filter: SubscriptionFilter{DeadbandType: DeadbandAbsolute, Deadband: 0.5},
+			values: []any{10.0, 10.3, 10.5, 10.6, 10.2, 11.2},
+			want:   []any{10.0, 10.6, 11.2},
+		},
+		{
+			name:   "percent deadband of range",
+			filter: SubscriptionFilter{DeadbandType: DeadbandPercent, Deadband: 1, RangeLow: 0, RangeHigh: 200},
+			values: []any{int16(100), int16(102), int16(103), int16(100)},
+			want:   []any{int16(100), int16(103), int16(100)},
+		},
+		{
+			name:   "hysteresis on direction reversal",
+			filter: SubscriptionFilter{DeadbandType: DeadbandAbsolute, Deadband: 1, Hysteresis: 2},
+			values: []any{0.0, 2.0, 0.5, -1.5, 4.0},
+			want:   []any{0.0, 2.0, -1.5, 4.0},
+		},
+		{
+			name:   "min interval",
+			filter: SubscriptionFilter{MinInterval: 250 * time.Millisecond},
+			values: []any{1, 2, 3, 4, 5, 6},
+			want:   []any{1, 4},
+		},
+		{
+			name:   "non-numeric values pass the deadband",
+			filter: SubscriptionFilter{DeadbandType: DeadbandAbsolute, Deadband: 5},
+			values: []any{"a", "b"},
+			want:   []any{"a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, applyFilter(tt.filter, tt.values...))
+		})
+	}
+}
+
+func TestSubscriptionFilterValidate(t *testing.T) {
+	assert.NoError(t, SubscriptionFilter{}.validate())
+	assert.NoError(t, SubscriptionFilter{DeadbandType: DeadbandPercent, Deadband: 2, RangeHigh: 100}.validate())
+	assert.Error(t, SubscriptionFilter{DeadbandType: DeadbandAbsolute, Deadband: -1}.validate())
+	assert.Error(t, SubscriptionFilter{DeadbandType: DeadbandPercent, Deadband: 2}.validate(), "percent without range")
+	assert.Error(t, SubscriptionFilter{DeadbandType: DeadbandPercent, Deadband: 101, RangeHigh: 1}.validate())
+	assert.Error(t, SubscriptionFilter{Hysteresis: -1}.validate())
+	assert.Error(t, SubscriptionFilter{MinInterval: -time.Second}.validate())
+
+	// A deadband needs a numeric variable
+	filter := SubscriptionFilter{DeadbandType: DeadbandAbsolute, Deadband: 1}
+	raw := subscriptionSpec{isRaw: true, settings: SubscriptionSettings{Filter: filter}}
+	assert.Error(t, raw.prepare(adsserializer.Options{}))
+	numeric := subscriptionSpec{dataType: &types.AdsDataType{DataType: types.ADST_REAL32, Size: 4}, settings: SubscriptionSettings{Filter: filter}}
+	assert.NoError(t, numeric.prepare(adsserializer.Options{}))
+	str := subscriptionSpec{dataType: &types.AdsDataType{DataType: types.ADST_STRING, Size: 81}, settings: SubscriptionSettings{Filter: filter}}
+	assert.Error(t, str.prepare(adsserializer.Options{}))
+	enum := subscriptionSpec{dataType: &types.AdsDataType{DataType: types.ADST_INT16, Size: 2, EnumInfo: []types.AdsEnumInfo{{Name: "Idle"}}}, settings: SubscriptionSettings{Filter: filter}}
+	assert.Error(t, enum.prepare(adsserializer.Options{}))
+	alias := subscriptionSpec{dataType: &types.AdsDataType{Type: "T_Timeout", DataType: types.ADST_UINT32, Size: 4}, settings: SubscriptionSettings{Filter: filter}}
+	assert.NoError(t, alias.prepare(adsserializer.Options{}), "unlisted alias is an integer")
+	assert.Error(t, alias.prepare(adsserializer.Options{TimeAliases: map[string]adsserializer.TimeType{"T_Timeout": adsserializer.TimeTypeTime}}))
+}
@@ -0,0 +1,223 @@
+package ads
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	adserrors "github.com/jarmocluyse/ads-go/pkg/ads/ads-errors"
+	adsheader "github.com/jarmocluyse/ads-go/pkg/ads/ads-header"
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+)
+
+// unknownHandleFloodLimit is the number of consecutive samples with unknown
+// notification handles from one port after which the subscriptions of that
+// port are told that the PLC and the client disagree about the handles.
+const unknownHandleFloodLimit = 100
+
+// SubscriptionState is the health of a subscription.
+type SubscriptionState int32
+
+const (
+	// SubscriptionStateActive means values are delivered.
+	SubscriptionStateActive SubscriptionState = iota
+	// SubscriptionStateSuspended means the subscription is registered but no
+	// values arrive for now: the target is not in Run (notifications) or the
+	// last read failed (polled). It resumes by itself.
+	SubscriptionStateSuspended
+	// SubscriptionStateDead means the subscription was removed or invalidated
+	// and never delivers again. Subscribe again to get new values.
+	SubscriptionStateDead
+)
+
+// String returns the string representation of the subscription state.
+func (s SubscriptionState) String() string {
+	switch s {
+	case SubscriptionStateActive:
+		return "Active"
+	case SubscriptionStateSuspended:
+		return "Suspended"
+	case SubscriptionStateDead:
+		return "Dead"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// State returns the current health of the subscription.
+func (s *ActiveSubscription) State() SubscriptionState {
+	return SubscriptionState(s.state.Load())
+}
+
+// setSuspended moves the subscription between Active and Suspended. Returns
+// true if the state changed. A dead subscription stays dead.
+func (s *ActiveSubscription) setSuspended(suspended bool) bool {
+	from, to := SubscriptionStateActive, SubscriptionStateSuspended
+	if !suspended {
+		from, to = to, from
+	}
+	return s.state.CompareAndSwap(int32(from), int32(to))
+}
+
+// reportSubscriptionError calls the OnError callback of sub with err.
+func (c *Client) reportSubscriptionError(sub *ActiveSubscription, err error) {
+	c.logger.Warn("Subscription error", "handle", sub.Handle, "port", sub.Port, "error", err)
+	if sub.Settings.OnError == nil {
+		return
+	}
+	c.invokeHook("OnError", func() { sub.Settings.OnError(sub, err) })
+}
+
+// invalidateSubscriptions removes the subscriptions for which match returns
+// true, marks them dead and calls their OnInvalidated callbacks with err.
+// The callbacks run on a separate goroutine, so they may send requests even
+// when invalidation is triggered from the receive goroutine.
+func (c *Client) invalidateSubscriptions(err error, match func(sub *ActiveSubscription) bool) {
+	var invalidated []*ActiveSubscription
+	for _, sub := range c.subscriptionList() {
+		if !match(sub) {
+			continue
+		}
+		c.removeSubscription(sub)
+		sub.close(err)
+		c.logger.Warn("Subscription invalidated", "handle", sub.Handle, "port", sub.Port, "error", err)
+		invalidated = append(invalidated, sub)
+	}
+	c.pruneMultiplexGroups()
+
+	go func() {
+		for _, sub := range invalidated {
+			if sub.Settings.OnInvalidated != nil {
+				c.invokeHook("OnInvalidated", func() { sub.Settings.OnInvalidated(sub, err) })
+			}
+		}
+	}()
+}
+
+// handleSubscriptionsLost is called when the connection is lost. Notification
+// handles do not survive the connection, so those subscriptions and all channel
+// subscriptions are invalidated. Polled subscriptions are suspended and resume
+// with the next successful read after a reconnect.
+func (c *Client) handleSubscriptionsLost(err error) {
+	c.invalidateSubscriptions(err, func(sub *ActiveSubscription) bool {
+		return sub.poll == nil || sub.onTerminate != nil
+	})
+	for _, sub := range c.subscriptionList() {
+		sub.setSuspended(true)
+	}
+}
+
+// suspendNotifications suspends or resumes the notification subscriptions
+// when the target leaves or enters Run. Polled subscriptions track their own
+// reads.
+func (c *Client) suspendNotifications(suspended bool) {
+	for _, sub := range c.subscriptionList() {
+		if sub.poll == nil {
+			sub.setSuspended(suspended)
+		}
+	}
+}
+
+// recordUnknownHandle counts a sample with an unknown notification handle from
+// port. A flood of them means the PLC lost or replaced our handles (e.g. after
+// a download), so the subscriptions of the port get an OnError.
+// Called from the receive goroutine with receiveMutex held.
+func (c *Client) recordUnknownHandle(port uint16, handle uint32) {
+	c.unknownHandles[port]++
+	if c.unknownHandles[port] < unknownHandleFloodLimit {
+		return
+	}
+	c.unknownHandles[port] = 0
+	err := fmt.Errorf("%w: %d consecutive samples from port %d (last handle %d)", ErrUnknownNotificationHandles, unknownHandleFloodLimit, port, handle)
+	subs := c.subscriptionList()
+	go func() {
+		for _, sub := range subs {
+			if sub.Port == port && sub.poll == nil {
+				c.reportSubscriptionError(sub, err)
+			}
+		}
+	}()
+}
+
+// checkSymbolVersions reads the symbol version of every port with
+// subscriptions. When it changed (the PLC program was downloaded), the
+// subscriptions of that port are invalidated, because their handles and
+// addresses are no longer valid. Their notifications are deleted on the PLC
+// first.
+func (c *Client) checkSymbolVersions() {
+	ports := make(map[uint16]bool)
+	for _, sub := range c.subscriptionList() {
+		ports[sub.Port] = true
+	}
+
+	for port := range ports {
+		data, err := c.ReadRaw(port, uint32(types.ADSReservedIndexGroupSymbolVersion), 0, 1)
+		if err != nil || len(data) < 1 {
+			if !errors.Is(err, ErrNotConnected) {
+				c.logger.Debug("checkSymbolVersions: Failed to read symbol version", "port", port, "error", err)
+			}
+			continue
+		}
+
+		c.stateMutex.Lock()
+		old, known := c.symbolVersions[port]
+		c.symbolVersions[port] = data[0]
+		c.stateMutex.Unlock()
+
+		if known && old != data[0] {
+			c.logger.Info("checkSymbolVersions: Symbol version changed", "port", port, "from", old, "to", data[0])
+			var handles []uint32
+			for _, sub := range c.subscriptionList() {
+				if sub.Port == port && !sub.clientSide {
+					handles = append(handles, sub.Handle)
+				}
+			}
+			c.deleteNotificationHandles(port, handles)
+			err := fmt.Errorf("%w: port %d (%d → %d)", ErrSymbolVersionChanged, port, old, data[0])
+			c.invalidateSubscriptions(err, func(sub *ActiveSubscription) bool { return sub.Port == port })
+		}
+	}
+}
+
+// deleteNotificationHandles deletes notification handles on the PLC that are
+// about to be dropped, with one sum command if the target supports it, so
+// they do not count against the notification limit of the target. Failures
+// are only logged: the target may have removed them already.
+func (c *Client) deleteNotificationHandles(port uint16, handles []uint32) {
+	for len(handles) > maxSumCommandItems {
+		c.deleteNotificationHandles(port, handles[:maxSumCommandItems])
+		handles = handles[maxSumCommandItems:]
+	}
+	if len(handles) == 0 {
+		return
+	}
+	if len(handles) > 1 && c.sumCommandsSupported() {
+		writeData := make([]byte, 4*len(handles))
+		for n, handle := range handles {
+			binary.LittleEndian.PutUint32(writeData[n*4:], handle)
+		}
+		_, err := c.sumCommand(port, types.ADSReservedIndexGroupSumCommandDelDevNote, len(handles), 4*len(handles), writeData, c.send)
+		if !errors.Is(err, adserrors.ErrAdsError) {
+			if err != nil {
+				c.logger.Debug("deleteNotificationHandles: Failed to delete notification handles", "port", port, "error", err)
+			}
+			return
+		}
+		c.logger.Debug("deleteNotificationHandles: Sum command rejected, deleting one by one", "port", port, "error", err)
+	}
+	for _, handle := range handles {
+		payload := make([]byte, 4)
+		binary.LittleEndian.PutUint32(payload, handle)
+		response, err := c.send(AdsCommandRequest{
+			Command:    types.ADSCommandDeleteNotification,
+			TargetPort: port,
+			Data:       payload,
+		})
+		if err == nil {
+			_, err = adsheader.StripAdsHeader(response)
+		}
+		if err != nil {
+			c.logger.Debug("deleteNotificationHandles: Failed to delete notification handle", "port", port, "handle", handle, "error", err)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package ads
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// healthEvents records OnError and OnInvalidated calls.
+type healthEvents struct {
+	mu          sync.Mutex
+	errs        []error
+	invalidated []error
+}
+
+func (e *healthEvents) settings() SubscriptionSettings {
+	return SubscriptionSettings{
+		OnError: func(_ *ActiveSubscription, err error) {
+			e.mu.Lock()
+			defer e.mu.Unlock()
+			e.errs = append(e.errs, err)
+		},
+		OnInvalidated: func(_ *ActiveSubscription, err error) {
+			e.mu.Lock()
+			defer e.mu.Unlock()
+			e.invalidated = append(e.invalidated, err)
+		},
+	}
+}
+
+func (e *healthEvents) counts() (int, int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.errs), len(e.invalidated)
+}
+
+// connectHealthTestClient connects a client to a fake router that answers
+// AddNotification with handle 1 and reports version as symbol version.
+func connectHealthTestClient(t *testing.T, version *atomic.Uint32) (*Client, *fakeRouter) {
+	router := newFakeRouter(t, func(cmd types.ADSCommand, port uint16, data []byte) []byte {
+		switch cmd {
+		case types.ADSCommandAddNotification:
+			resp := make([]byte, 8)
+			binary.LittleEndian.PutUint32(resp[4:8], 1)
+			return resp
+		case types.ADSCommandRead:
+			if types.ADSReservedIndexGroup(binary.LittleEndian.Uint32(data[0:4])) == types.ADSReservedIndexGroupSymbolVersion {
+				return []byte{0, 0, 0, 0, 1, 0, 0, 0, byte(version.Load())}
+			}
+		}
+		return defaultFakeHandler(cmd, port, data)
+	})
+	c := NewClient(router.settings(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	t.Cleanup(func() { _ = c.Disconnect() })
+	return c, router
+}
+
+// subscribeInt32 subscribes to a DINT at a raw address, parsed like SubscribeValue.
+func subscribeInt32(t *testing.T, c *Client, settings SubscriptionSettings) *ActiveSubscription {
+	sub, err := c.addSubscription(subscriptionSpec{
+		port: 851, indexGroup: 0x4020, size: 4,
+		dataType: &types.AdsDataType{DataType: types.ADST_INT32, Size: 4},
+		callback: func(SubscriptionData) {},
+		settings: settings,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return sub
+}
+
+// TestSubscriptionOnError verifies that parse errors and unknown-handle
+// floods are reported without ending the subscription.
+func TestSubscriptionOnError(t *testing.T) {
+	var version atomic.Uint32
+	c, router := connectHealthTestClient(t, &version)
+	var events healthEvents
+	sub := subscribeInt32(t, c, events.settings())
+
+	router.notify(851, 1, []byte{1}) // too short for a DINT
+	waitFor(t, time.Second, func() bool { n, _ := events.counts(); return n == 1 }, "parse error")
+
+	for range unknownHandleFloodLimit {
+		router.notify(851, 99, []byte{1, 2, 3, 4})
+	}
+	waitFor(t, time.Second, func() bool { n, _ := events.counts(); return n == 2 }, "unknown handle flood")
+
+	events.mu.Lock()
+	assert.True(t, errors.Is(events.errs[1], ErrUnknownNotificationHandles), "got %v", events.errs[1])
+	assert.Empty(t, events.invalidated)
+	events.mu.Unlock()
+	assert.Equal(t, SubscriptionStateActive, sub.State())
+}
+
+// TestSubscriptionInvalidatedOnSymbolVersionChange verifies that a new
+// symbol version invalidates the subscriptions of the port.
+func TestSubscriptionInvalidatedOnSymbolVersionChange(t *testing.T) {
+	var version atomic.Uint32
+	version.Store(3)
+	c, _ := connectHealthTestClient(t, &version)
+	var events healthEvents
+	sub := subscribeInt32(t, c, events.settings())
+
+	c.checkSymbolVersions()
+	assert.Equal(t, SubscriptionStateActive, sub.State())
+
+	version.Store(4)
+	c.checkSymbolVersions()
+	waitFor(t, time.Second, func() bool { _, n := events.counts(); return n == 1 }, "OnInvalidated")
+	events.mu.Lock()
+	assert.True(t, errors.Is(events.invalidated[0], ErrSymbolVersionChanged), "got %v", events.invalidated[0])
+	events.mu.Unlock()
+	assert.Equal(t, SubscriptionStateDead, sub.State())
+	assert.Empty(t, c.Debug().Subscriptions)
+}
+
+// TestSymbolVersionChangeDeletesHandles verifies that the notifications of
+// invalidated subscriptions are deleted on the PLC, also when the target
+// rejects sum commands.
+func TestSymbolVersionChangeDeletesHandles(t *testing.T) {
+	for _, sumCommands := range []bool{true, false} {
+		var version atomic.Uint32
+		var mu sync.Mutex
+		var deleted []uint32
+		router := newFakeRouter(t, func(cmd types.ADSCommand, port uint16, data []byte) []byte {
+			mu.Lock()
+			defer mu.Unlock()
+			switch cmd {
+			case types.ADSCommandRead:
+				if types.ADSReservedIndexGroup(binary.LittleEndian.Uint32(data[0:4])) == types.ADSReservedIndexGroupSymbolVersion {
+					return []byte{0, 0, 0, 0, 1, 0, 0, 0, byte(version.Load())}
+				}
+			case types.ADSCommandReadWrite:
+				if indexGroup, count := sumCommandHeader(data); indexGroup == types.ADSReservedIndexGroupSumCommandDelDevNote {
+					if !sumCommands {
+						resp := make([]byte, 8)
+						binary.LittleEndian.PutUint32(resp[0:4], 1793) // service not supported
+						return resp
+					}
+					for n := range count {
+						deleted = append(deleted, binary.LittleEndian.Uint32(data[16+4*n:]))
+					}
+				}
+			case types.ADSCommandDeleteNotification:
+				deleted = append(deleted, binary.LittleEndian.Uint32(data[0:4]))
+			}
+			return defaultFakeHandler(cmd, port, data)
+		})
+		c := NewClient(router.settings(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+		if err := c.Connect(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		results, err := c.SubscribeMany([]SubscriptionRequest{
+			{Port: 851, IndexGroup: 0x4020, IndexOffset: 0, Size: 4, Callback: func(SubscriptionData) {}},
+			{Port: 851, IndexGroup: 0x4020, IndexOffset: 4, Size: 4, Callback: func(SubscriptionData) {}},
+			{Port: 851, IndexGroup: 0x4020, IndexOffset: 8, Size: 4, Callback: func(SubscriptionData) {},
+				Settings: SubscriptionSettings{Mode: SubscriptionModePolled}},
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		c.checkSymbolVersions()
+		version.Store(1)
+		c.checkSymbolVersions()
+
+		mu.Lock()
+		assert.ElementsMatch(t, []uint32{results[0].Subscription.Handle, results[1].Subscription.Handle}, deleted,
+			"notifications deleted, polled subscription has none (sum commands: %v)", sumCommands)
+		mu.Unlock()
+		assert.Empty(t, c.Debug().Subscriptions)
+		_ = c.Disconnect()
+	}
+}
+
+// TestSubscriptionStateOnConnectionLoss verifies that notification
+// subscriptions are invalidated and polled subscriptions are suspended.
+func TestSubscriptionStateOnConnectionLoss(t *testing.T) {
+	var version atomic.Uint32
+	c, router := connectHealthTestClient(t, &version)
+	var events healthEvents
+	notified := subscribeInt32(t, c, events.settings())
+	polledSettings := SubscriptionSettings{CycleTime: 5 * time.Minute, Mode: SubscriptionModePolled}
+	polled := subscribeInt32(t, c, polledSettings)
+
+	c.updateTargetHealth(false)
+	assert.Equal(t, SubscriptionStateSuspended, notified.State())
+	c.updateTargetHealth(true)
+	assert.Equal(t, SubscriptionStateActive, notified.State())
+
+	router.dropConnection()
+	waitFor(t, time.Second, func() bool { _, n := events.counts(); return n == 1 }, "OnInvalidated")
+	events.mu.Lock()
+	assert.True(t, errors.Is(events.invalidated[0], ErrNotConnected), "got %v", events.invalidated[0])
+	events.mu.Unlock()
+	assert.Equal(t, SubscriptionStateDead, notified.State())
+	assert.Equal(t, SubscriptionStateSuspended, polled.State())
+	assert.Len(t, c.Debug().Subscriptions, 1, "polled subscription stays registered")
+}
@@ -0,0 +1,175 @@
+package ads
+
+import (
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDurationTo100ns verifies the conversion to AddNotification time units.
+func TestDurationTo100ns(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    time.Duration
+		expected uint32
+		wantErr  bool
+	}{
+		{"zero", 0, 0, false},
+		{"250us", 250 * time.Microsecond, 2500, false},
+		{"500us", 500 * time.Microsecond, 5000, false},
+		{"100ms", 100 * time.Millisecond, 1000000, false},
+		{"100ns", 100 * time.Nanosecond, 1, false},
+		{"not a multiple of 100ns", 150 * time.Nanosecond, 0, true},
+		{"negative", -time.Millisecond, 0, true},
+		{"too large", 430 * time.Second, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			units, err := durationTo100ns(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, units)
+		})
+	}
+}
+
+// TestSubscriptionSettingsValidate verifies settings validation.
+func TestSubscriptionSettingsValidate(t *testing.T) {
+	assert.NoError(t, SubscriptionSettings{}.Validate())
+	assert.NoError(t, SubscriptionSettings{CycleTime: 250 * time.Microsecond, TransmissionMode: types.ADSTransModeCyclicInContext}.Validate())
+	assert.Error(t, SubscriptionSettings{CycleTime: 150 * time.Nanosecond}.Validate())
+	assert.Error(t, SubscriptionSettings{MaxDelay: -time.Second}.Validate())
+	assert.Error(t, SubscriptionSettings{QueueSize: -1}.Validate())
+	assert.Error(t, SubscriptionSettings{OverflowPolicy: OverflowPolicy(9)}.Validate())
+	assert.Error(t, SubscriptionSettings{TransmissionMode: types.ADSTransMode(7)}.Validate())
+}
+
+// TestEffectiveTransmissionMode verifies the fallback to SendOnChange.
+func TestEffectiveTransmissionMode(t *testing.T) {
+	assert.Equal(t, types.ADSTransModeCyclic, SubscriptionSettings{}.EffectiveTransmissionMode())
+	assert.Equal(t, types.ADSTransModeOnChange, SubscriptionSettings{SendOnChange: true}.EffectiveTransmissionMode())
+	assert.Equal(t, types.ADSTransModeOnChangeInContext,
+		SubscriptionSettings{SendOnChange: false, TransmissionMode: types.ADSTransModeOnChangeInContext}.EffectiveTransmissionMode())
+}
+
+// TestAddNotificationPayload verifies mode and 100ns timing in the request.
+func TestAddNotificationPayload(t *testing.T) {
+	var mu sync.Mutex
+	var request []byte
+	router := newFakeRouter(t, func(cmd types.ADSCommand, port uint16, data []byte) []byte {
+		if cmd == types.ADSCommandAddNotification {
+			mu.Lock()
+			request = append([]byte(nil), data...)
+			mu.Unlock()
+			resp := make([]byte, 8)
+			binary.LittleEndian.PutUint32(resp[4:8], 1)
+			return resp
+		}
+		return defaultFakeHandler(cmd, port, data)
+	})
+	c := NewClient(router.settings(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer func() { _ = c.Disconnect() }()
+
+	_, err := c.SubscribeRaw(851, 0x4020, 0, 4, func(SubscriptionData) {}, SubscriptionSettings{
+		CycleTime:        250 * time.Microsecond,
+		MaxDelay:         time.Millisecond,
+		TransmissionMode: types.ADSTransModeCyclicInContext,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	lastRequest := func() []byte {
+		mu.Lock()
+		defer mu.Unlock()
+		return request
+	}
+
+	req := lastRequest()
+	if len(req) != 40 {
+		t.Fatalf("Expected 40 byte request, got %d", len(req))
+	}
+	assert.Equal(t, uint32(types.ADSTransModeCyclicInContext), binary.LittleEndian.Uint32(req[12:16]))
+	assert.Equal(t, uint32(10000), binary.LittleEndian.Uint32(req[16:20]))
+	assert.Equal(t, uint32(2500), binary.LittleEndian.Uint32(req[20:24]))
+
+	// Invalid settings are rejected before anything is sent
+	mu.Lock()
+	request = nil
+	mu.Unlock()
+	_, err = c.SubscribeRaw(851, 0x4020, 0, 4, func(SubscriptionData) {}, SubscriptionSettings{CycleTime: 50 * time.Nanosecond})
+	assert.Error(t, err)
+	assert.Nil(t, lastRequest())
+}
+
+// TestClientOnChangeSubscription verifies the client-side emulation: no
+// notification is registered and only changed values are delivered.
+func TestClientOnChangeSubscription(t *testing.T) {
+	var value atomic.Uint32
+	var adds atomic.Int32
+	router := newFakeRouter(t, func(cmd types.ADSCommand, port uint16, data []byte) []byte {
+		switch cmd {
+		case types.ADSCommandAddNotification:
+			adds.Add(1)
+		case types.ADSCommandRead:
+			resp := make([]byte, 12)
+			binary.LittleEndian.PutUint32(resp[4:8], 4)
+			binary.LittleEndian.PutUint32(resp[8:12], value.Load())
+			return resp
+		}
+		return defaultFakeHandler(cmd, port, data)
+	})
+	c := NewClient(router.settings(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer func() { _ = c.Disconnect() }()
+
+	var mu sync.Mutex
+	var received []uint32
+	sub, err := c.SubscribeRaw(851, 0x4020, 0, 4, func(data SubscriptionData) {
+		mu.Lock()
+		received = append(received, binary.LittleEndian.Uint32(data.RawValue))
+		mu.Unlock()
+	}, SubscriptionSettings{CycleTime: 5 * time.Millisecond, TransmissionMode: types.ADSTransModeClientOnChange})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	count := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(received)
+	}
+	waitFor(t, time.Second, func() bool { return count() == 1 }, "initial value")
+	time.Sleep(30 * time.Millisecond) // several unchanged polls
+	assert.Equal(t, 1, count())
+
+	value.Store(7)
+	waitFor(t, time.Second, func() bool { return count() == 2 }, "changed value")
+
+	assert.Equal(t, int32(0), adds.Load())
+	assert.True(t, c.Debug().Subscriptions[0].ClientSide)
+
+	if err := c.Unsubscribe(sub); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Empty(t, c.Debug().Subscriptions)
+	mu.Lock()
+	assert.Equal(t, []uint32{0, 7}, received)
+	mu.Unlock()
+}
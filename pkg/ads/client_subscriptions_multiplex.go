@@ -0,0 +1,263 @@
+package ads
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	adssymbol "github.com/jarmocluyse/ads-go/pkg/ads/ads-symbol"
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+)
+
+// multiplexGroup shares one notification on a parent symbol between the
+// multiplexed subscriptions of its members. Every parent sample is sliced
+// by the member offsets and a member only receives its slice when it changed.
+type multiplexGroup struct {
+	key     string              // key in Client.multiplexGroups
+	parent  *ActiveSubscription // notification on the parent symbol
+	mu      sync.Mutex          // protects members
+	members map[*ActiveSubscription]*multiplexMember
+}
+
+// multiplexMember is the slice of the parent buffer that belongs to a member.
+type multiplexMember struct {
+	offset uint32 // offset in the parent buffer (SubItems offset)
+	size   uint32 // size of the member
+	last   []byte // last delivered slice (nil = nothing delivered yet)
+}
+
+// multiplexParent splits path into the parent symbol path and member name.
+// The parent is the immediate parent (see SubscriptionSettings.Multiplex).
+// Returns false if path has no parent or addresses an array element.
+func multiplexParent(path string) (parent, member string, ok bool) {
+	idx := strings.LastIndex(path, ".")
+	if idx <= 0 || idx == len(path)-1 {
+		return "", "", false
+	}
+	parent, member = path[:idx], path[idx+1:]
+	if strings.ContainsAny(member, "[]^") {
+		return "", "", false
+	}
+	return parent, member, true
+}
+
+// multiplexKey identifies the group that a multiplexed subscription joins.
+// Only subscriptions with the same timing can share a notification.
+func multiplexKey(port uint16, parentPath string, settings SubscriptionSettings) string {
+	return fmt.Sprintf("%d|%s|%d|%d|%d|%d", port, strings.ToLower(parentPath),
+		settings.Mode, settings.EffectiveTransmissionMode(), settings.CycleTime, settings.MaxDelay)
+}
+
+// findSubItem returns the sub item of dataType with the given name (case-insensitive like TwinCAT).
+func findSubItem(dataType types.AdsDataType, name string) (types.AdsDataType, bool) {
+	for _, item := range dataType.SubItems {
+		if strings.EqualFold(item.Name, name) {
+			return item, true
+		}
+	}
+	return types.AdsDataType{}, false
+}
+
+// subscribeMultiplexed subscribes to path through the notification of its
+// parent symbol. ok is false if path cannot be multiplexed (no parent symbol
+// or the parent is not a structure); the caller then subscribes normally.
+func (c *Client) subscribeMultiplexed(port uint16, path string, callback SubscriptionCallback, settings SubscriptionSettings, onTerminate func(err error)) (sub *ActiveSubscription, ok bool, err error) {
+	parentPath, memberName, ok := multiplexParent(path)
+	if !ok {
+		return nil, false, nil
+	}
+
+	// The filter is checked against the member type in joinMultiplexGroup
+	spec := subscriptionSpec{settings: settings}
+	if err := spec.prepareSettings(); err != nil {
+		return nil, true, fmt.Errorf("SubscribeValue: %w", err)
+	}
+	settings = spec.settings
+
+	c.multiplexMutex.Lock()
+	defer c.multiplexMutex.Unlock()
+
+	group := c.multiplexGroups[multiplexKey(port, parentPath, settings)]
+	var parentType types.AdsDataType
+	var parentSymbol *adssymbol.AdsSymbol
+	if group != nil {
+		parentSymbol, parentType = group.parent.Symbol, *group.parent.DataType
+	} else {
+		// A parent that is not a symbol (e.g. a GVL name) cannot be multiplexed
+		parentSymbol, err = c.GetSymbol(port, parentPath)
+		if err != nil {
+			c.logger.Debug("SubscribeValue: Parent is not a symbol, not multiplexing", "path", path, "parent", parentPath)
+			return nil, false, nil
+		}
+		parentType, err = c.GetDataType(parentSymbol.Type, port)
+		if err != nil {
+			return nil, true, fmt.Errorf("SubscribeValue: failed to get data type of parent %q: %w", parentPath, err)
+		}
+	}
+
+	return c.joinMultiplexGroup(port, parentSymbol, parentType, memberName, path, callback, settings, onTerminate)
+}
+
+// joinMultiplexGroup adds a member to the group of parentSymbol, creating the
+// group and its notification if needed. The caller must hold multiplexMutex.
+func (c *Client) joinMultiplexGroup(port uint16, parentSymbol *adssymbol.AdsSymbol, parentType types.AdsDataType, memberName, path string, callback SubscriptionCallback, settings SubscriptionSettings, onTerminate func(err error)) (*ActiveSubscription, bool, error) {
+	item, found := findSubItem(parentType, memberName)
+	if !found {
+		c.logger.Debug("SubscribeValue: Member not found in parent data type, not multiplexing", "path", path)
+		return nil, false, nil
+	}
+	if item.Offset+item.Size > parentSymbol.Size {
+		return nil, true, fmt.Errorf("SubscribeValue: member %q exceeds parent size %d", path, parentSymbol.Size)
+	}
+
+	// The member is parsed from its own slice, not from the parent buffer
+	dataType := item
+	dataType.Offset = 0
+	spec := subscriptionSpec{
+		port:        port,
+		indexGroup:  parentSymbol.IndexGroup,
+		indexOffset: parentSymbol.IndexOffset + item.Offset,
+		size:        item.Size,
+		callback:    callback,
+		settings:    settings,
+		symbol: &adssymbol.AdsSymbol{
+			IndexGroup:  parentSymbol.IndexGroup,
+			IndexOffset: parentSymbol.IndexOffset + item.Offset,
+			Size:        item.Size,
+			DataType:    item.DataType,
+			Name:        path,
+			Type:        item.Type,
+		},
+		dataType:    &dataType,
+		onTerminate: onTerminate,
+	}
+
+	if err := spec.validateFilter(c.serializerOptions()); err != nil {
+		return nil, true, fmt.Errorf("SubscribeValue: %w", err)
+	}
+
+	key := multiplexKey(port, parentSymbol.Name, settings)
+	group := c.multiplexGroups[key]
+	if group == nil {
+		group = &multiplexGroup{key: key, members: make(map[*ActiveSubscription]*multiplexMember)}
+		parentSettings := settings
+		parentSettings.Multiplex = false
+		parentSettings.Filter = SubscriptionFilter{}
+		parentSettings.OnError = nil
+		parentSettings.OnInvalidated = nil
+		parentSettings.QueueSize = DefaultQueueSize
+		parentSettings.OverflowPolicy = OverflowDropOldest
+		dataType := parentType
+		parent, err := c.addSubscription(subscriptionSpec{
+			port:        port,
+			indexGroup:  parentSymbol.IndexGroup,
+			indexOffset: parentSymbol.IndexOffset,
+			size:        parentSymbol.Size,
+			callback:    func(data SubscriptionData) { c.dispatchMultiplexed(group, data) },
+			settings:    parentSettings,
+			symbol:      parentSymbol,
+			dataType:    &dataType,
+			isRaw:       true,
+		})
+		if err != nil {
+			return nil, true, fmt.Errorf("SubscribeValue: failed to subscribe to parent %q: %w", parentSymbol.Name, err)
+		}
+		group.parent = parent
+		c.multiplexGroups[key] = group
+		c.logger.Info("SubscribeValue: Multiplex group created", "parent", parentSymbol.Name, "handle", parent.Handle)
+	}
+
+	c.subscriptionsMutex.Lock()
+	c.lastClientHandle++
+	sub := spec.newSubscription(c.lastClientHandle)
+	sub.clientSide = true
+	sub.multiplex = group
+	c.clientSubscriptions[sub.Handle] = sub
+	c.subscriptionsMutex.Unlock()
+
+	group.mu.Lock()
+	group.members[sub] = &multiplexMember{offset: item.Offset, size: item.Size}
+	group.mu.Unlock()
+
+	c.logger.Info("SubscribeValue: Multiplexed subscription created", "handle", sub.Handle, "path", path, "parentHandle", group.parent.Handle)
+	return sub, true, nil
+}
+
+// dispatchMultiplexed slices a parent sample and queues the changed slices
+// for the members.
+func (c *Client) dispatchMultiplexed(group *multiplexGroup, data SubscriptionData) {
+	type delivery struct {
+		sub     *ActiveSubscription
+		payload []byte
+	}
+	var deliveries []delivery
+
+	group.mu.Lock()
+	for sub, member := range group.members {
+		end := member.offset + member.size
+		if int(end) > len(data.RawValue) {
+			continue
+		}
+		slice := data.RawValue[member.offset:end]
+		if member.last != nil && bytes.Equal(slice, member.last) {
+			continue
+		}
+		member.last = bytes.Clone(slice)
+		deliveries = append(deliveries, delivery{sub: sub, payload: member.last})
+	}
+	group.mu.Unlock()
+
+	// Queue outside the lock, OverflowBlock may wait for a member callback
+	now := time.Now()
+	for _, d := range deliveries {
+		d.sub.recordNotification(now)
+		c.enqueueNotification(d.sub, d.payload, data.Timestamp)
+	}
+}
+
+// leaveMultiplexGroup removes a member from its group. When the last member
+// leaves, the parent notification is deleted with send.
+func (c *Client) leaveMultiplexGroup(sub *ActiveSubscription, send func(AdsCommandRequest) ([]byte, error)) error {
+	group := sub.multiplex
+
+	c.multiplexMutex.Lock()
+	defer c.multiplexMutex.Unlock()
+
+	group.mu.Lock()
+	delete(group.members, sub)
+	empty := len(group.members) == 0
+	group.mu.Unlock()
+
+	if !empty || c.multiplexGroups[group.key] != group {
+		return nil
+	}
+	delete(c.multiplexGroups, group.key)
+	c.logger.Info("Unsubscribe: Last multiplexed member removed, deleting parent notification", "parentHandle", group.parent.Handle)
+	if !c.isSubscribed(group.parent) {
+		return nil
+	}
+	return c.unsubscribe(group.parent, send)
+}
+
+// parentHandle returns the handle of the shared parent notification of a
+// multiplexed member, 0 for other subscriptions.
+func (sub *ActiveSubscription) parentHandle() uint32 {
+	if sub.multiplex == nil {
+		return 0
+	}
+	return sub.multiplex.parent.Handle
+}
+
+// pruneMultiplexGroups forgets the groups whose parent notification was
+// removed, so new members create a fresh parent.
+func (c *Client) pruneMultiplexGroups() {
+	c.multiplexMutex.Lock()
+	defer c.multiplexMutex.Unlock()
+	for key, group := range c.multiplexGroups {
+		if !c.isSubscribed(group.parent) {
+			delete(c.multiplexGroups, key)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package ads
+
+import (
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	adssymbol "github.com/jarmocluyse/ads-go/pkg/ads/ads-symbol"
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiplexParent(t *testing.T) {
+	tests := []struct {
+		path   string
+		parent string
+		member string
+		ok     bool
+	}{
+		{"MAIN.Axis.Position", "MAIN.Axis", "Position", true},
+		{"GVL.Counter", "GVL", "Counter", true},
+		{"Counter", "", "", false},
+		{"MAIN.Values[2]", "", "", false},
+		{"MAIN.pAxis^", "", "", false},
+		{"MAIN.", "", "", false},
+	}
+	for _, tt := range tests {
+		parent, member, ok := multiplexParent(tt.path)
+		assert.Equal(t, tt.ok, ok, tt.path)
+		assert.Equal(t, tt.parent, parent, tt.path)
+		assert.Equal(t, tt.member, member, tt.path)
+	}
+}
+
+// TestMultiplexedSubscriptions verifies that members share one notification,
+// only receive their own slice when it changed and that the parent
+// notification is deleted with the last member.
+func TestMultiplexedSubscriptions(t *testing.T) {
+	var adds, deletes atomic.Int32
+	router := newFakeRouter(t, func(cmd types.ADSCommand, port uint16, data []byte) []byte {
+		switch cmd {
+		case types.ADSCommandAddNotification:
+			adds.Add(1)
+			resp := make([]byte, 8)
+			binary.LittleEndian.PutUint32(resp[4:8], 7)
+			return resp
+		case types.ADSCommandDeleteNotification:
+			deletes.Add(1)
+		}
+		return defaultFakeHandler(cmd, port, data)
+	})
+	c := NewClient(router.settings(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer func() { _ = c.Disconnect() }()
+
+	parent := &adssymbol.AdsSymbol{IndexGroup: 0x4020, IndexOffset: 100, Size: 6, Name: "MAIN.Axis", Type: "ST_Axis"}
+	parentType := types.AdsDataType{Name: "ST_Axis", Size: 6, SubItems: []types.AdsDataType{
+		{Name: "Position", Type: "DINT", DataType: types.ADST_INT32, Size: 4, Offset: 0},
+		{Name: "Status", Type: "INT", DataType: types.ADST_INT16, Size: 2, Offset: 4},
+	}}
+	settings := SubscriptionSettings{CycleTime: 100 * time.Millisecond, Multiplex: true, QueueSize: DefaultQueueSize}
+
+	var mu sync.Mutex
+	var positions, statuses []any
+	join := func(member string, received *[]any) *ActiveSubscription {
+		c.multiplexMutex.Lock()
+		defer c.multiplexMutex.Unlock()
+		sub, ok, err := c.joinMultiplexGroup(851, parent, parentType, member, "MAIN.Axis."+member, func(data SubscriptionData) {
+			mu.Lock()
+			defer mu.Unlock()
+			*received = append(*received, data.Value)
+		}, settings, nil)
+		if !ok || err != nil {
+			t.Fatalf("Expected multiplexed subscription, got ok=%v err=%v", ok, err)
+		}
+		return sub
+	}
+	position := join("Position", &positions)
+	status := join("status", &statuses)
+	assert.Equal(t, int32(1), adds.Load(), "members share one notification")
+	assert.Same(t, position.multiplex, status.multiplex)
+
+	sample := func(pos int32, st int16) []byte {
+		buf := make([]byte, 6)
+		binary.LittleEndian.PutUint32(buf[0:4], uint32(pos))
+		binary.LittleEndian.PutUint16(buf[4:6], uint16(st))
+		return buf
+	}
+	router.notify(851, 7, sample(10, 1))
+	router.notify(851, 7, sample(20, 1)) // status unchanged
+	router.notify(851, 7, sample(20, 2)) // position unchanged
+	waitFor(t, time.Second, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(positions) == 2 && len(statuses) == 2
+	}, "member callbacks")
+	mu.Lock()
+	assert.Equal(t, []any{int32(10), int32(20)}, positions)
+	assert.Equal(t, []any{int16(1), int16(2)}, statuses)
+	mu.Unlock()
+
+	if err := c.Unsubscribe(position); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, int32(0), deletes.Load(), "parent stays while members remain")
+	if err := c.Unsubscribe(status); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, int32(1), deletes.Load(), "parent is deleted with the last member")
+	assert.Empty(t, c.Debug().Subscriptions)
+	assert.Empty(t, c.multiplexGroups)
+}
+
+// TestMultiplexedUnsubscribeAll verifies that UnsubscribeAll deletes the
+// shared parent notification only once.
+func TestMultiplexedUnsubscribeAll(t *testing.T) {
+	var deleted atomic.Int32
+	router := newFakeRouter(t, func(cmd types.ADSCommand, port uint16, data []byte) []byte {
+		switch cmd {
+		case types.ADSCommandAddNotification:
+			resp := make([]byte, 8)
+			binary.LittleEndian.PutUint32(resp[4:8], 7)
+			return resp
+		case types.ADSCommandDeleteNotification:
+			deleted.Add(1)
+		case types.ADSCommandReadWrite:
+			if indexGroup, count := sumCommandHeader(data); indexGroup == types.ADSReservedIndexGroupSumCommandDelDevNote {
+				deleted.Add(int32(count))
+			}
+		}
+		return defaultFakeHandler(cmd, port, data)
+	})
+	c := NewClient(router.settings(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer func() { _ = c.Disconnect() }()
+
+	parent := &adssymbol.AdsSymbol{IndexGroup: 0x4020, Size: 2, Name: "GVL.Flags"}
+	parentType := types.AdsDataType{Size: 2, SubItems: []types.AdsDataType{
+		{Name: "A", DataType: types.ADST_UINT8, Size: 1, Offset: 0},
+		{Name: "B", DataType: types.ADST_UINT8, Size: 1, Offset: 1},
+	}}
+	settings := SubscriptionSettings{CycleTime: 100 * time.Millisecond, Multiplex: true, QueueSize: DefaultQueueSize}
+	c.multiplexMutex.Lock()
+	for _, member := range []string{"A", "B"} {
+		if _, _, err := c.joinMultiplexGroup(851, parent, parentType, member, "GVL.Flags."+member, func(SubscriptionData) {}, settings, nil); err != nil {
+			c.multiplexMutex.Unlock()
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	c.multiplexMutex.Unlock()
+	assert.Len(t, c.Debug().Subscriptions, 3)
+
+	if err := c.UnsubscribeAll(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, int32(1), deleted.Load())
+	assert.Empty(t, c.Debug().Subscriptions)
+}
+
+// TestMultiplexedFilter verifies that a deadband is accepted on a numeric
+// member and applied to its slice of the parent notification.
+func TestMultiplexedFilter(t *testing.T) {
+	router := newFakeRouter(t, func(cmd types.ADSCommand, port uint16, data []byte) []byte {
+		reply := func(payload []byte) []byte {
+			resp := make([]byte, 8, 8+len(payload))
+			binary.LittleEndian.PutUint32(resp[4:8], uint32(len(payload)))
+			return append(resp, payload...)
+		}
+		switch cmd {
+		case types.ADSCommandReadWrite:
+			name := strings.TrimRight(string(data[16:]), "\x00")
+			switch types.ADSReservedIndexGroup(binary.LittleEndian.Uint32(data[0:4])) {
+			case types.ADSReservedIndexGroupSymbolInfoByNameEx:
+				if name == "MAIN.Axis" {
+					return reply(symbolEntry(name, "ST_Axis", 0x4020, 100, 6, 0))
+				}
+			case types.ADSReservedIndexGroupDataDataTypeInfoByNameEx:
+				switch name {
+				case "ST_Axis":
+					return reply(dataTypeEntry(name, "", 6, 0, types.ADST_BIGTYPE, types.ADSDataTypeFlagDataType,
+						dataTypeEntry("Position", "DINT", 4, 0, types.ADST_INT32, types.ADSDataTypeFlagDataType),
+						dataTypeEntry("Name", "STRING(1)", 2, 4, types.ADST_STRING, types.ADSDataTypeFlagDataType)))
+				case "DINT":
+					return reply(dataTypeEntry(name, "", 4, 0, types.ADST_INT32, types.ADSDataTypeFlagDataType))
+				case "STRING(1)":
+					return reply(dataTypeEntry(name, "", 2, 0, types.ADST_STRING, types.ADSDataTypeFlagDataType))
+				}
+			}
+			resp := make([]byte, 8)
+			binary.LittleEndian.PutUint32(resp[0:4], 1808) // symbol not found
+			return resp
+		case types.ADSCommandAddNotification:
+			resp := make([]byte, 8)
+			binary.LittleEndian.PutUint32(resp[4:8], 7)
+			return resp
+		}
+		return defaultFakeHandler(cmd, port, data)
+	})
+	c := NewClient(router.settings(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer func() { _ = c.Disconnect() }()
+
+	settings := SubscriptionSettings{
+		CycleTime: 100 * time.Millisecond,
+		Multiplex: true,
+		Filter:    SubscriptionFilter{DeadbandType: DeadbandAbsolute, Deadband: 5},
+	}
+	var mu sync.Mutex
+	var positions []any
+	sub, err := c.SubscribeValue(851, "MAIN.Axis.Position", func(data SubscriptionData) {
+		mu.Lock()
+		defer mu.Unlock()
+		positions = append(positions, data.Value)
+	}, settings)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.NotNil(t, sub.multiplex, "subscribed through the parent")
+
+	_, err = c.SubscribeValue(851, "MAIN.Axis.Name", func(SubscriptionData) {}, settings)
+	assert.ErrorContains(t, err, "deadband and hysteresis require a numeric variable")
+
+	sample := func(pos int32) []byte {
+		buf := make([]byte, 6)
+		binary.LittleEndian.PutUint32(buf[0:4], uint32(pos))
+		return buf
+	}
+	router.notify(851, 7, sample(10))
+	router.notify(851, 7, sample(12)) // within the deadband
+	router.notify(851, 7, sample(20))
+	waitFor(t, time.Second, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(positions) == 2
+	}, "member callbacks")
+	mu.Lock()
+	assert.Equal(t, []any{int32(10), int32(20)}, positions)
+	mu.Unlock()
+	assert.Equal(t, uint64(1), sub.FilteredSamples())
+}
@@ -0,0 +1,238 @@
+package ads
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	adserrors "github.com/jarmocluyse/ads-go/pkg/ads/ads-errors"
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+)
+
+// pollGroup reads the values of all polled subscriptions with the same port
+// and CycleTime together. Every cycle the members are read with one sum read
+// (up to 500 per request) and fed into their queues like notifications.
+type pollGroup struct {
+	key      string        // key in Client.pollGroups
+	port     uint16        // target ADS port of all members
+	interval time.Duration // CycleTime of all members
+	wake     chan struct{} // signalled when a member joins, so its first value is read at once
+	done     chan struct{} // closed when the last member left
+
+	mu      sync.Mutex                          // protects members and noSum
+	members map[*ActiveSubscription]*pollMember // members of the group
+	noSum   bool                                // the target rejected sum reads, read one by one
+}
+
+// pollMember is the read state of one polled subscription. Only the poll
+// goroutine of the group accesses last and started.
+type pollMember struct {
+	onChange bool   // deliver only changed values
+	started  bool   // a value was read at least once
+	last     []byte // last delivered value
+}
+
+// pollItem is a member taken for one poll cycle.
+type pollItem struct {
+	sub    *ActiveSubscription
+	member *pollMember
+}
+
+// addPolledSubscription creates a subscription that is read by the client
+// instead of being notified by the PLC (SubscriptionModePolled and the
+// client-side transmission modes). The subscription gets a local handle and
+// joins the poll group of its port and CycleTime.
+func (c *Client) addPolledSubscription(spec subscriptionSpec) *ActiveSubscription {
+	c.subscriptionsMutex.Lock()
+	c.lastClientHandle++
+	sub := spec.newSubscription(c.lastClientHandle)
+	sub.clientSide = true
+	c.subscriptionsMutex.Unlock()
+
+	// Join before registering, so removeSubscription always sees the group
+	c.joinPollGroup(sub)
+
+	c.subscriptionsMutex.Lock()
+	c.clientSubscriptions[sub.Handle] = sub
+	c.subscriptionsMutex.Unlock()
+	c.logger.Info("addSubscription: Polled subscription created", "handle", sub.Handle, "port", sub.Port, "mode", sub.Settings.EffectiveTransmissionMode().String())
+	return sub
+}
+
+// joinPollGroup adds sub to the poll group of its port and CycleTime and
+// starts the group if it is new.
+func (c *Client) joinPollGroup(sub *ActiveSubscription) {
+	c.pollMutex.Lock()
+	defer c.pollMutex.Unlock()
+
+	key := fmt.Sprintf("%d|%d", sub.Port, sub.Settings.CycleTime)
+	group := c.pollGroups[key]
+	if group == nil {
+		group = &pollGroup{
+			key:      key,
+			port:     sub.Port,
+			interval: sub.Settings.CycleTime,
+			wake:     make(chan struct{}, 1),
+			done:     make(chan struct{}),
+			members:  make(map[*ActiveSubscription]*pollMember),
+		}
+		c.pollGroups[key] = group
+		go c.runPollGroup(group)
+	}
+
+	group.mu.Lock()
+	group.members[sub] = &pollMember{onChange: sub.Settings.pollOnChange()}
+	group.mu.Unlock()
+	sub.poll = group
+
+	select {
+	case group.wake <- struct{}{}:
+	default:
+	}
+}
+
+// leavePollGroup removes sub from its poll group and stops the group when it
+// runs empty.
+func (c *Client) leavePollGroup(sub *ActiveSubscription) {
+	c.pollMutex.Lock()
+	defer c.pollMutex.Unlock()
+
+	group := sub.poll
+	group.mu.Lock()
+	delete(group.members, sub)
+	empty := len(group.members) == 0
+	group.mu.Unlock()
+
+	if empty && c.pollGroups[group.key] == group {
+		delete(c.pollGroups, group.key)
+		close(group.done)
+	}
+}
+
+// runPollGroup reads the members of group every interval until the group is
+// stopped. Members that joined since the last cycle are read at once.
+func (c *Client) runPollGroup(group *pollGroup) {
+	ticker := time.NewTicker(group.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-group.done:
+			return
+		case <-ticker.C:
+			c.pollMembers(group, group.items(false))
+		case <-group.wake:
+			c.pollMembers(group, group.items(true))
+		}
+	}
+}
+
+// items returns the current members, or only those not read yet if onlyNew is set.
+func (group *pollGroup) items(onlyNew bool) []pollItem {
+	group.mu.Lock()
+	defer group.mu.Unlock()
+	items := make([]pollItem, 0, len(group.members))
+	for sub, member := range group.members {
+		if onlyNew && member.started {
+			continue
+		}
+		items = append(items, pollItem{sub: sub, member: member})
+	}
+	return items
+}
+
+// pollMembers reads items and delivers their values. A single item is read
+// with ReadRaw, more are combined into sum reads.
+func (c *Client) pollMembers(group *pollGroup, items []pollItem) {
+	group.mu.Lock()
+	noSum := group.noSum
+	group.mu.Unlock()
+
+	if len(items) == 1 || noSum || !c.sumCommandsSupported() {
+		for _, item := range items {
+			data, err := c.ReadRaw(group.port, item.sub.indexGroup, item.sub.indexOffset, item.sub.size)
+			c.deliverPolled(item, data, err)
+		}
+		return
+	}
+
+	for start := 0; start < len(items); start += maxSumCommandItems {
+		chunk := items[start:min(start+maxSumCommandItems, len(items))]
+		values, errs, err := c.sumRead(group.port, chunk)
+		if errors.Is(err, adserrors.ErrAdsError) {
+			c.logger.Warn("pollMembers: Sum read rejected, reading one by one", "port", group.port, "error", err)
+			group.mu.Lock()
+			group.noSum = true
+			group.mu.Unlock()
+			c.pollMembers(group, chunk)
+			continue
+		}
+		for n, item := range chunk {
+			if err != nil {
+				c.deliverPolled(item, nil, err)
+				continue
+			}
+			c.deliverPolled(item, values[n], errs[n])
+		}
+	}
+}
+
+// deliverPolled queues a read value of a polled subscription. A failed read
+// suspends the subscription (OnError is called once per outage), it keeps
+// polling and resumes with the next successful read.
+func (c *Client) deliverPolled(item pollItem, data []byte, err error) {
+	sub, member := item.sub, item.member
+	if err != nil {
+		c.logger.Debug("pollMembers: Read failed", "handle", sub.Handle, "error", err)
+		if sub.setSuspended(true) {
+			c.reportSubscriptionError(sub, fmt.Errorf("pollMembers: read failed: %w", err))
+		}
+		return
+	}
+	sub.setSuspended(false)
+	if member.onChange && member.started && bytes.Equal(data, member.last) {
+		return // Unchanged
+	}
+	member.started = true
+	member.last = data
+	now := time.Now()
+	sub.recordNotification(now)
+	c.enqueueNotification(sub, data, now)
+}
+
+// sumRead reads the values of items (all on port) with one SumCommandRead
+// request. It returns the value and error of every item.
+func (c *Client) sumRead(port uint16, items []pollItem) ([][]byte, []error, error) {
+	// Request: per item [0..3] index group, [4..7] index offset, [8..11] length
+	writeData := make([]byte, 12*len(items))
+	readLength := 4 * len(items)
+	for n, item := range items {
+		binary.LittleEndian.PutUint32(writeData[n*12:], item.sub.indexGroup)
+		binary.LittleEndian.PutUint32(writeData[n*12+4:], item.sub.indexOffset)
+		binary.LittleEndian.PutUint32(writeData[n*12+8:], item.sub.size)
+		readLength += int(item.sub.size)
+	}
+
+	// Response: per item [0..3] error code, followed by the data of all items
+	data, err := c.sumCommand(port, types.ADSReservedIndexGroupSumCommandRead, len(items), readLength, writeData, c.send)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values := make([][]byte, len(items))
+	errs := make([]error, len(items))
+	pos := 4 * len(items)
+	for n, item := range items {
+		end := pos + int(item.sub.size)
+		if err := adserrors.CheckAdsError(data[n*4 : n*4+4]); err != nil {
+			errs[n] = fmt.Errorf("sumRead: read failed for handle %d: %w", item.sub.Handle, err)
+		} else {
+			values[n] = bytes.Clone(data[pos:end])
+		}
+		pos = end
+	}
+	return values, errs, nil
+}
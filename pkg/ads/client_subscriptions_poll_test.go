@@ -0,0 +1,148 @@
+package ads
+
+import (
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// pollTestRouter answers sum reads and reads of 4-byte values with the index
+// offset plus value. If rejectSum is set, sum reads fail with an ADS error.
+func pollTestRouter(t *testing.T, value *atomic.Uint32, sumReads, reads *atomic.Int32, rejectSum bool) *fakeRouter {
+	return newFakeRouter(t, func(cmd types.ADSCommand, port uint16, data []byte) []byte {
+		switch cmd {
+		case types.ADSCommandAddNotification:
+			t.Errorf("Expected no AddNotification for polled subscriptions")
+		case types.ADSCommandRead:
+			reads.Add(1)
+			resp := make([]byte, 12)
+			binary.LittleEndian.PutUint32(resp[4:8], 4)
+			binary.LittleEndian.PutUint32(resp[8:12], binary.LittleEndian.Uint32(data[4:8])+value.Load())
+			return resp
+		case types.ADSCommandReadWrite:
+			indexGroup, count := sumCommandHeader(data)
+			if indexGroup != types.ADSReservedIndexGroupSumCommandRead {
+				break
+			}
+			sumReads.Add(1)
+			if rejectSum {
+				resp := make([]byte, 8)
+				binary.LittleEndian.PutUint32(resp[0:4], 1793) // service not supported
+				return resp
+			}
+			items := data[16:]
+			resp := make([]byte, 8+8*count)
+			binary.LittleEndian.PutUint32(resp[4:8], uint32(8*count))
+			for n := range count {
+				offset := binary.LittleEndian.Uint32(items[n*12+4:])
+				binary.LittleEndian.PutUint32(resp[8+4*count+4*n:], offset+value.Load())
+			}
+			return resp
+		}
+		return defaultFakeHandler(cmd, port, data)
+	})
+}
+
+// subscribePolled subscribes to three raw values polled in one group and
+// returns the values received per index offset.
+func subscribePolled(t *testing.T, c *Client, settings SubscriptionSettings) (func() map[uint32][]uint32, []*ActiveSubscription) {
+	var mu sync.Mutex
+	received := make(map[uint32][]uint32)
+	var subs []*ActiveSubscription
+	for _, offset := range []uint32{100, 200, 300} {
+		sub, err := c.SubscribeRaw(851, 0x4020, offset, 4, func(data SubscriptionData) {
+			mu.Lock()
+			defer mu.Unlock()
+			received[offset] = append(received[offset], binary.LittleEndian.Uint32(data.RawValue))
+		}, settings)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		subs = append(subs, sub)
+	}
+	snapshot := func() map[uint32][]uint32 {
+		mu.Lock()
+		defer mu.Unlock()
+		result := make(map[uint32][]uint32, len(received))
+		for offset, values := range received {
+			result[offset] = append([]uint32(nil), values...)
+		}
+		return result
+	}
+	return snapshot, subs
+}
+
+// TestPolledSubscriptionsSumRead verifies that polled subscriptions with the
+// same cycle time are read with sum reads and only deliver changes.
+func TestPolledSubscriptionsSumRead(t *testing.T) {
+	var value atomic.Uint32
+	var sumReads, reads atomic.Int32
+	router := pollTestRouter(t, &value, &sumReads, &reads, false)
+	c := NewClient(router.settings(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer func() { _ = c.Disconnect() }()
+
+	received, subs := subscribePolled(t, c, SubscriptionSettings{
+		CycleTime: 5 * time.Millisecond, SendOnChange: true, Mode: SubscriptionModePolled,
+	})
+	assert.Len(t, c.pollGroups, 1, "same port and cycle time share a poll group")
+
+	waitFor(t, time.Second, func() bool { return len(received()[300]) == 1 }, "initial values")
+	waitFor(t, time.Second, func() bool { return sumReads.Load() >= 3 }, "sum reads")
+	value.Store(1)
+	waitFor(t, time.Second, func() bool {
+		values := received()
+		return len(values[100]) == 2 && len(values[200]) == 2 && len(values[300]) == 2
+	}, "changed values")
+
+	values := received()
+	assert.Equal(t, []uint32{100, 101}, values[100])
+	assert.Equal(t, []uint32{200, 201}, values[200])
+	assert.Equal(t, []uint32{300, 301}, values[300])
+	assert.True(t, c.Debug().Subscriptions[0].Polled)
+
+	for _, sub := range subs {
+		if err := c.Unsubscribe(sub); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	assert.Empty(t, c.pollGroups)
+	assert.Empty(t, c.Debug().Subscriptions)
+}
+
+// TestPolledSubscriptionsFallback verifies that targets without sum read
+// support are read one value at a time.
+func TestPolledSubscriptionsFallback(t *testing.T) {
+	var value atomic.Uint32
+	var sumReads, reads atomic.Int32
+	router := pollTestRouter(t, &value, &sumReads, &reads, true)
+	c := NewClient(router.settings(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer func() { _ = c.Disconnect() }()
+
+	received, _ := subscribePolled(t, c, SubscriptionSettings{
+		CycleTime: 5 * time.Millisecond, Mode: SubscriptionModePolled,
+	})
+	waitFor(t, time.Second, func() bool {
+		values := received()
+		return len(values[100]) >= 3 && len(values[200]) >= 3 && len(values[300]) >= 3
+	}, "cyclic values")
+	assert.Equal(t, int32(1), sumReads.Load(), "sum reads are not retried")
+	assert.Equal(t, uint32(200), received()[200][0])
+
+	if err := c.UnsubscribeAll(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Empty(t, c.pollGroups)
+}
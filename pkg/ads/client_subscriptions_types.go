@@ -1,6 +1,10 @@
 package ads
 
 import (
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	adssymbol "github.com/jarmocluyse/ads-go/pkg/ads/ads-symbol"
@@ -12,6 +16,7 @@ type SubscriptionSettings struct {
 	// CycleTime is how often the PLC checks for value changes (default: 200ms).
 	// If SendOnChange is true, PLC checks if value has changed with CycleTime interval.
 	// If SendOnChange is false, PLC constantly sends the value with CycleTime interval.
+	// The PLC works in 100ns units, so CycleTime must be a multiple of 100ns (max ~429s).
 	CycleTime time.Duration
 
 	// SendOnChange determines if notifications are sent only when the value changes (default: true).
@@ -24,7 +29,194 @@ type SubscriptionSettings struct {
 	// If value is not changing, first notification after subscribing is sent after MaxDelay.
 	// If the value is changing, PLC sends one or more notifications every MaxDelay.
 	// This can be useful for throttling high-frequency changes.
+	// Like CycleTime, MaxDelay must be a multiple of 100ns (max ~429s).
+	// Ignored for polled subscriptions.
 	MaxDelay time.Duration
+
+	// TransmissionMode selects how notifications are produced (default: derived from SendOnChange).
+	// If ADSTransModeNone, SendOnChange selects ADSTransModeOnChange or ADSTransModeCyclic.
+	//   - ADSTransModeCyclic, ADSTransModeOnChange: the PLC samples in its ADS server.
+	//   - ADSTransModeCyclicInContext, ADSTransModeOnChangeInContext: the PLC samples in the
+	//     context of the task that owns the variable (use for sub-millisecond CycleTime).
+	//   - ADSTransModeClientCycle, ADSTransModeClientOnChange: the subscription is polled
+	//     like SubscriptionModePolled. For ClientOnChange only changed values are delivered.
+	TransmissionMode types.ADSTransMode
+
+	// QueueSize is how many samples may wait for the callback (default: 64).
+	// Samples are delivered to the callback one at a time, in the order they were received.
+	// Ignored for OverflowKeepLatest, which keeps a single sample.
+	QueueSize int
+
+	// OverflowPolicy decides what happens when the queue is full (default: OverflowDropOldest).
+	OverflowPolicy OverflowPolicy
+
+	// Filter suppresses samples before the callback is called, e.g. a deadband
+	// for noisy analog values (default: no filtering). See SubscriptionFilter.
+	Filter SubscriptionFilter
+
+	// OnError is called when a sample cannot be delivered, the subscription
+	// keeps running: the value cannot be parsed, a polled read fails (once per
+	// outage) or the target floods the client with unknown notification handles
+	// (ErrUnknownNotificationHandles). Optional.
+	OnError func(sub *ActiveSubscription, err error)
+
+	// OnInvalidated is called once when the subscription ends without being
+	// unsubscribed: the connection was lost (ErrNotConnected) or the symbol
+	// version of the port changed (ErrSymbolVersionChanged). The subscription is
+	// already removed and its State is SubscriptionStateDead. It runs on its own
+	// goroutine, so it may subscribe again. Optional.
+	OnInvalidated func(sub *ActiveSubscription, err error)
+
+	// Mode selects how values are obtained (default: SubscriptionModeNotification).
+	// With SubscriptionModePolled no notification is registered on the PLC: the
+	// client reads the value every CycleTime, like the client-side transmission
+	// modes. Use it for targets that reject AddNotification or for values above
+	// the notification size limit. With an on-change TransmissionMode (or
+	// SendOnChange) only changed values are delivered.
+	// Polled subscriptions with the same port and CycleTime are read together
+	// with sum reads.
+	Mode SubscriptionMode
+
+	// Multiplex shares one notification on the parent symbol between the members
+	// of a structure (default: false). SubscribeValue on "X.a" and "X.b" with
+	// Multiplex and the same timing uses a single notification on "X"; each callback
+	// only fires when its own member changed. Only used by SubscribeValue and
+	// SubscribeValueChan. Paths without a structure parent are subscribed normally.
+	//
+	// Members are grouped by their immediate parent, not by the topmost common
+	// symbol: "X.a.b" and "X.c" use two notifications, on "X.a" and on "X". This
+	// keeps the notification as small as the structure that contains the member
+	// instead of e.g. a whole function block instance.
+	Multiplex bool
+}
+
+// Validate checks the settings before they are sent to the PLC.
+// Zero values are valid and replaced by their defaults when subscribing.
+func (s SubscriptionSettings) Validate() error {
+	if _, err := durationTo100ns(s.CycleTime); err != nil {
+		return fmt.Errorf("invalid CycleTime: %w", err)
+	}
+	if _, err := durationTo100ns(s.MaxDelay); err != nil {
+		return fmt.Errorf("invalid MaxDelay: %w", err)
+	}
+	if s.QueueSize < 0 {
+		return fmt.Errorf("invalid QueueSize %d: must not be negative", s.QueueSize)
+	}
+	if s.OverflowPolicy < OverflowDropOldest || s.OverflowPolicy > OverflowKeepLatest {
+		return fmt.Errorf("invalid OverflowPolicy %d", s.OverflowPolicy)
+	}
+	if s.TransmissionMode > types.ADSTransModeOnChangeInContext {
+		return fmt.Errorf("invalid TransmissionMode %d", s.TransmissionMode)
+	}
+	if s.Mode < SubscriptionModeNotification || s.Mode > SubscriptionModePolled {
+		return fmt.Errorf("invalid Mode %d", s.Mode)
+	}
+	if err := s.Filter.validate(); err != nil {
+		return fmt.Errorf("invalid Filter: %w", err)
+	}
+	return nil
+}
+
+// EffectiveTransmissionMode returns the transmission mode used for the subscription.
+// If TransmissionMode is not set, it is derived from SendOnChange.
+func (s SubscriptionSettings) EffectiveTransmissionMode() types.ADSTransMode {
+	if s.TransmissionMode != types.ADSTransModeNone {
+		return s.TransmissionMode
+	}
+	if s.SendOnChange {
+		return types.ADSTransModeOnChange
+	}
+	return types.ADSTransModeCyclic
+}
+
+// isPolled reports whether the subscription is read by the client instead of
+// being notified by the PLC.
+func (s SubscriptionSettings) isPolled() bool {
+	mode := s.EffectiveTransmissionMode()
+	return s.Mode == SubscriptionModePolled || mode == types.ADSTransModeClientCycle || mode == types.ADSTransModeClientOnChange
+}
+
+// pollOnChange reports whether a polled subscription only delivers changed values.
+func (s SubscriptionSettings) pollOnChange() bool {
+	switch s.EffectiveTransmissionMode() {
+	case types.ADSTransModeOnChange, types.ADSTransModeOnChangeInContext, types.ADSTransModeClientOnChange:
+		return true
+	default:
+		return false
+	}
+}
+
+// SubscriptionMode selects how the values of a subscription are obtained.
+type SubscriptionMode int
+
+const (
+	// SubscriptionModeNotification registers a device notification on the PLC.
+	SubscriptionModeNotification SubscriptionMode = iota
+	// SubscriptionModePolled reads the value every CycleTime from the client.
+	SubscriptionModePolled
+)
+
+// String returns the string representation of the subscription mode.
+func (m SubscriptionMode) String() string {
+	switch m {
+	case SubscriptionModeNotification:
+		return "Notification"
+	case SubscriptionModePolled:
+		return "Polled"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// durationTo100ns converts d to the 100ns units used by AddNotification.
+// Returns an error if d is negative, not a multiple of 100ns or does not fit in 32 bits.
+func durationTo100ns(d time.Duration) (uint32, error) {
+	const unit = 100 * time.Nanosecond
+	if d < 0 {
+		return 0, fmt.Errorf("%s is negative", d)
+	}
+	if d%unit != 0 {
+		return 0, fmt.Errorf("%s is not a multiple of 100ns", d)
+	}
+	units := d / unit
+	if units > math.MaxUint32 {
+		return 0, fmt.Errorf("%s exceeds the maximum of %s", d, time.Duration(math.MaxUint32)*unit)
+	}
+	return uint32(units), nil
+}
+
+// DefaultQueueSize is the notification queue size used when SubscriptionSettings.QueueSize is 0.
+const DefaultQueueSize = 64
+
+// OverflowPolicy decides what happens to new samples when the notification
+// queue of a subscription is full.
+type OverflowPolicy int
+
+const (
+	// OverflowDropOldest discards the oldest queued sample to make room for the new one.
+	OverflowDropOldest OverflowPolicy = iota
+	// OverflowBlock waits until the callback has taken a sample.
+	// WARNING: This stalls the receive goroutine, so responses and the samples of
+	//          all other subscriptions are delayed until there is room. A callback that
+	//          sends requests itself can run into the request timeout.
+	OverflowBlock
+	// OverflowKeepLatest keeps only the most recent sample. Samples that arrive
+	// while the callback runs replace each other.
+	OverflowKeepLatest
+)
+
+// String returns the string representation of the overflow policy.
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowDropOldest:
+		return "DropOldest"
+	case OverflowBlock:
+		return "Block"
+	case OverflowKeepLatest:
+		return "KeepLatest"
+	default:
+		return "UNKNOWN"
+	}
 }
 
 // SubscriptionData contains the parsed value and timestamp from a subscription notification.
@@ -38,6 +230,10 @@ type SubscriptionData struct {
 
 	// Timestamp is when the PLC sampled the value.
 	Timestamp time.Time
+
+	// Err is only set on the last value of a channel subscription (SubscribeValueChan)
+	// and tells why the channel is closed. Callbacks never receive a non-nil Err.
+	Err error
 }
 
 // SubscriptionCallback is called when a subscribed value changes or when cycle time elapses.
@@ -66,6 +262,55 @@ type ActiveSubscription struct {
 	// IsRaw indicates if this is a raw subscription (SubscribeRaw).
 	// If true, data is not parsed and Value = RawValue in callback.
 	IsRaw bool
+
+	lastNotification  atomic.Int64      // unix nanoseconds of the last received sample (0 = none yet)
+	notificationCount atomic.Uint64     // number of samples received for this subscription
+	droppedSamples    atomic.Uint64     // number of samples discarded by the overflow policy
+	filteredSamples   atomic.Uint64     // number of samples suppressed by the filter
+	filter            filterState       // state of Settings.Filter (notification worker only)
+	state             atomic.Int32      // SubscriptionState
+	internal          bool              // used by the client itself, hidden from the user
+	queue             notificationQueue // samples waiting for the callback
+	onTerminate       func(err error)   // called once when the subscription ends (channel subscriptions)
+	indexGroup        uint32            // subscribed index group
+	indexOffset       uint32            // subscribed index offset
+	size              uint32            // subscribed data length
+	clientSide        bool              // no own PLC notification, Handle is local (polled, multiplexed members)
+	multiplex         *multiplexGroup   // group of a multiplexed member (nil otherwise)
+	poll              *pollGroup        // poll group of a polled subscription (nil otherwise)
+	terminateOnce     sync.Once         // guards onTerminate
+}
+
+// LastNotification returns when the last sample for this subscription was received.
+// Returns the zero time if no notification has arrived yet.
+func (s *ActiveSubscription) LastNotification() time.Time {
+	nanos := s.lastNotification.Load()
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// NotificationCount returns the number of samples received for this subscription.
+func (s *ActiveSubscription) NotificationCount() uint64 {
+	return s.notificationCount.Load()
+}
+
+// DroppedSamples returns the number of samples discarded because the
+// notification queue was full.
+func (s *ActiveSubscription) DroppedSamples() uint64 {
+	return s.droppedSamples.Load()
+}
+
+// FilteredSamples returns the number of samples suppressed by Settings.Filter.
+func (s *ActiveSubscription) FilteredSamples() uint64 {
+	return s.filteredSamples.Load()
+}
+
+// recordNotification updates the receive statistics of the subscription.
+func (s *ActiveSubscription) recordNotification(at time.Time) {
+	s.lastNotification.Store(at.UnixNano())
+	s.notificationCount.Add(1)
 }
 
 // notificationStamp represents a timestamp with multiple notification samples.
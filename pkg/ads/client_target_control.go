@@ -0,0 +1,111 @@
+package ads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+)
+
+// Device states of the Shutdown control on the system service.
+const (
+	targetShutdownDeviceState uint16 = 0
+	targetRebootDeviceState   uint16 = 1
+)
+
+// targetRestartPollInterval is how often WaitForTargetRun reconnects or reads
+// the system state while the target is coming back.
+const targetRestartPollInterval = time.Second
+
+// RebootTarget reboots the target system (IPC).
+//
+// The client closes the connection once the command is sent. The connection
+// drop is expected, so OnConnectionLost is not called; subscriptions are
+// invalidated as on any connection loss. Use WaitForTargetRun to wait for the
+// target to come back.
+//
+// Once the command is sent, a missing response (timeout, connection loss or
+// ctx ending) counts as success, since the target may go down before it
+// answers. ctx bounds the wait for the response; if it has ended before the
+// command is sent, its error is returned.
+//
+// Example:
+//
+//	if err := client.RebootTarget(ctx); err != nil {
+//	    return err
+//	}
+//	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+//	defer cancel()
+//	if err := client.WaitForTargetRun(ctx); err != nil {
+//	    return err
+//	}
+func (c *Client) RebootTarget(ctx context.Context) error {
+	return c.controlTarget(ctx, "RebootTarget", targetRebootDeviceState)
+}
+
+// ShutdownTarget shuts the target system (IPC) down. The connection is closed
+// like in RebootTarget without calling OnConnectionLost.
+func (c *Client) ShutdownTarget(ctx context.Context) error {
+	return c.controlTarget(ctx, "ShutdownTarget", targetShutdownDeviceState)
+}
+
+// controlTarget sends the Shutdown control with deviceState to the system
+// service and tears the connection down without reporting it as lost.
+func (c *Client) controlTarget(ctx context.Context, operationName string, deviceState uint16) error {
+	c.logger.Info(operationName+": Sending shutdown control to the system service", "deviceState", deviceState)
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%s: %w", operationName, err)
+	}
+	if _, _, err := c.activeConn(); err != nil {
+		return fmt.Errorf("%s: Use Connect() to connect to the target first: %w", operationName, err)
+	}
+
+	c.targetShutdown.Store(true)
+	defer c.targetShutdown.Store(false)
+
+	err := c.writeControl(ctx, types.ADSStateShutdown, deviceState, types.ADSReservedPortSystemService)
+	var unanswered *unansweredError
+	if errors.As(err, &unanswered) {
+		// The control was sent; the target may go down before it answers
+		c.logger.Info(operationName+": No response to the shutdown control, assuming the target is going down", "error", err)
+	} else if err != nil {
+		c.logger.Error(operationName+": Failed to send WriteControl command", "error", err)
+		return fmt.Errorf("%s: %w", operationName, err)
+	}
+
+	c.handleConnectionLost(nil, fmt.Errorf("%s: target is going down", operationName))
+	c.logger.Info(operationName + ": Target is going down, connection closed.")
+	return nil
+}
+
+// WaitForTargetRun waits until the target is reachable and the TwinCAT system
+// is in Run, e.g. after RebootTarget. It reconnects as needed and returns
+// ctx.Err() (wrapped) if ctx ends first.
+func (c *Client) WaitForTargetRun(ctx context.Context) error {
+	c.logger.Info("WaitForTargetRun: Waiting for the target to come back in Run")
+	for {
+		if _, _, err := c.activeConn(); err != nil {
+			err := c.Connect()
+			if err == nil {
+				continue // read the state right away
+			}
+			if errors.Is(err, ErrClientClosed) {
+				return fmt.Errorf("WaitForTargetRun: %w", err)
+			}
+			c.logger.Debug("WaitForTargetRun: Target not reachable yet", "error", err)
+		} else if state, err := c.ReadTcSystemState(); err != nil {
+			c.logger.Debug("WaitForTargetRun: Failed to read system state", "error", err)
+		} else if state.AdsState == types.ADSStateRun {
+			c.logger.Info("WaitForTargetRun: Target is in Run")
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("WaitForTargetRun: %w", ctx.Err())
+		case <-time.After(targetRestartPollInterval):
+		}
+	}
+}
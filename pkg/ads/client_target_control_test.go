@@ -0,0 +1,107 @@
+package ads
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRebootTarget verifies that RebootTarget sends the shutdown control,
+// closes the connection without OnConnectionLost and that WaitForTargetRun
+// reconnects.
+func TestRebootTarget(t *testing.T) {
+	var controls, deviceState atomic.Int32
+	var router *fakeRouter
+	router = newFakeRouter(t, func(cmd types.ADSCommand, port uint16, data []byte) []byte {
+		if cmd == types.ADSCommandWriteControl && port == uint16(types.ADSReservedPortSystemService) &&
+			types.ADSState(binary.LittleEndian.Uint16(data[0:2])) == types.ADSStateShutdown {
+			deviceState.Store(int32(binary.LittleEndian.Uint16(data[2:4])))
+			if controls.Add(1) == 2 {
+				// The target goes down before it answers
+				go router.dropConnection()
+				return nil
+			}
+			return make([]byte, 4)
+		}
+		return defaultFakeHandler(cmd, port, data)
+	})
+
+	var lost atomic.Int32
+	settings := router.settings()
+	settings.OnConnectionLost = func(*Client, error) { lost.Add(1) }
+	c := NewClient(settings, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer func() { _ = c.Disconnect() }()
+
+	if err := c.RebootTarget(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, int32(targetRebootDeviceState), deviceState.Load())
+	assert.Equal(t, ConnectionStateDisconnected, c.ConnectionState())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := c.WaitForTargetRun(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, ConnectionStateConnected, c.ConnectionState())
+
+	if err := c.ShutdownTarget(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, int32(targetShutdownDeviceState), deviceState.Load())
+	assert.Equal(t, ConnectionStateDisconnected, c.ConnectionState())
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(0), lost.Load(), "expected connection drop is not reported")
+
+	cancelled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	assert.ErrorIs(t, c.RebootTarget(cancelled), context.Canceled)
+}
+
+// TestRebootTargetUnanswered verifies that a sent shutdown control counts as
+// success when the target never answers, and that ctx bounds the wait.
+func TestRebootTargetUnanswered(t *testing.T) {
+	router := newFakeRouter(t, func(cmd types.ADSCommand, port uint16, data []byte) []byte {
+		if cmd == types.ADSCommandWriteControl {
+			return nil // the target goes down without answering
+		}
+		return defaultFakeHandler(cmd, port, data)
+	})
+	settings := router.settings()
+	settings.Timeout = 5 * time.Second
+	c := NewClient(settings, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer func() { _ = c.Disconnect() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := c.RebootTarget(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Less(t, time.Since(start), time.Second, "ctx ends the wait before the client timeout")
+	assert.Equal(t, ConnectionStateDisconnected, c.ConnectionState())
+
+	// Without a deadline the client timeout ends the wait
+	c.settings.Timeout = 100 * time.Millisecond
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := c.ShutdownTarget(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, ConnectionStateDisconnected, c.ConnectionState())
+}
@@ -0,0 +1,229 @@
+package ads
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	adserrors "github.com/jarmocluyse/ads-go/pkg/ads/ads-errors"
+	adsheader "github.com/jarmocluyse/ads-go/pkg/ads/ads-header"
+	adsrequests "github.com/jarmocluyse/ads-go/pkg/ads/ads-requests"
+	adsserializer "github.com/jarmocluyse/ads-go/pkg/ads/ads-serializer"
+	adsstateinfo "github.com/jarmocluyse/ads-go/pkg/ads/ads-stateinfo"
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+)
+
+// uploadInfoSize is the size of the symbol upload info (version 3) that
+// carries the upload flags.
+const uploadInfoSize = 64
+
+// uploadInfoFlagsOffset is the offset of the flags in the symbol upload info.
+const uploadInfoFlagsOffset = 32
+
+// probeSymbolName is the symbol the handle probe asks for. The target is not
+// expected to know it; "symbol not found" proves handles are supported.
+const probeSymbolName = "__ads_go_probe__"
+
+// TargetInfo is everything the client knows about the target, collected by
+// Connect (see ProbeTarget). The capability flags let features choose a
+// fallback up front instead of failing first.
+type TargetInfo struct {
+	DeviceInfo *adsstateinfo.DeviceInfo // ReadDeviceInfo of the system service (nil if it failed)
+
+	// TwinCAT version, from the extended state (or the device info if the
+	// extended state is not supported)
+	Version  uint8
+	Revision uint8
+	Build    uint16
+
+	Platform    uint8                            // platform id (extended state only)
+	OsType      uint8                            // operating system type id (extended state only)
+	SystemFlags types.ADSSystemServiceStateFlags // system service state flags (extended state only)
+	UploadFlags types.ADSUploadInfoFlags         // symbol upload flags of ClientSettings.TargetInfoPort
+
+	Is64Bit        bool                         // the PLC runtime is a 64 bit platform
+	Utf8Strings    bool                         // STRING data of the PLC runtime is UTF-8 encoded
+	StringEncoding adsserializer.StringEncoding // encoding ReadValue and WriteValue use for STRING data
+
+	ExtendedState bool // ReadTcSystemExtendedState is supported
+	SumCommands   bool // sum commands (e.g. SubscribeMany in one request) are supported
+	Handles       bool // symbol handles are supported
+	RPC           bool // method calls are possible (symbol handles on a TwinCAT 3 runtime)
+
+	ProbedAt time.Time // when the target was probed
+}
+
+// TargetInfo returns what the client found out about the target the last
+// time it connected. It returns nil until a probe completed, e.g. when the
+// target was unreachable while connecting. The result is a copy.
+func (c *Client) TargetInfo() *TargetInfo {
+	c.targetInfoMutex.RLock()
+	defer c.targetInfoMutex.RUnlock()
+	if c.targetInfo == nil {
+		return nil
+	}
+	info := *c.targetInfo
+	return &info
+}
+
+// ProbeTarget collects the device info, the extended state and the symbol
+// upload flags of the target and tests which optional services it supports.
+// The result is cached for TargetInfo; Connect calls it on every connect.
+//
+// Probes that the target answers with an ADS error, in the response or in the
+// AMS header (e.g. target port not found), mark the capability as not
+// supported. If a request is not answered at all (timeout, connection loss),
+// the probe is aborted, the cached info is left unchanged and the error is
+// returned.
+//
+// Example:
+//
+//	info, err := client.ProbeTarget()
+//	if err == nil && !info.SumCommands {
+//	    // subscribe one by one
+//	}
+func (c *Client) ProbeTarget() (*TargetInfo, error) {
+	c.logger.Debug("ProbeTarget: Probing target capabilities.")
+	info := &TargetInfo{}
+	port := c.settings.TargetInfoPort
+
+	if deviceInfo, err := c.ReadDeviceInfo(); err != nil {
+		c.logger.Warn("ProbeTarget: Failed to read device info", "error", err)
+	} else {
+		info.DeviceInfo = deviceInfo
+		info.Version = deviceInfo.MajorVersion
+		info.Revision = deviceInfo.MinorVersion
+		info.Build = deviceInfo.VersionBuild
+	}
+
+	// Extended state
+	data, err := c.probe(AdsCommandRequest{
+		Command:    types.ADSCommandRead,
+		TargetPort: types.ADSReservedPortSystemService,
+		Data:       adsrequests.BuildReadRequest(240, 0, 16),
+	})
+	if err != nil && !errors.Is(err, adserrors.ErrAdsError) {
+		return nil, fmt.Errorf("ProbeTarget: extended state: %w", err)
+	}
+	if extState, err := adsstateinfo.ParseExtendedSystemState(data); err == nil {
+		info.ExtendedState = true
+		info.Version = extState.Version
+		info.Revision = extState.Revision
+		info.Build = extState.Build
+		info.Platform = extState.Platform
+		info.OsType = extState.OsType
+		info.SystemFlags = types.ADSSystemServiceStateFlags(extState.Flags)
+	}
+
+	// Symbol upload flags
+	data, err = c.probe(AdsCommandRequest{
+		Command:    types.ADSCommandRead,
+		TargetPort: port,
+		Data:       adsrequests.BuildReadRequest(uint32(types.ADSReservedIndexGroupSymbolUploadInfo2), 0, uploadInfoSize),
+	})
+	if err != nil && !errors.Is(err, adserrors.ErrAdsError) {
+		return nil, fmt.Errorf("ProbeTarget: upload info: %w", err)
+	}
+	if len(data) >= uploadInfoFlagsOffset+4 {
+		info.UploadFlags = types.ADSUploadInfoFlags(binary.LittleEndian.Uint32(data[uploadInfoFlagsOffset:]))
+		info.Is64Bit = info.UploadFlags&types.ADSUploadInfoFlagIs64BitPlatform != 0
+		info.Utf8Strings = info.UploadFlags&types.ADSUploadInfoFlagUtf8EncodedStringData != 0
+		if !info.Utf8Strings {
+			// Runtimes without the flag use the Windows code page
+			info.StringEncoding = adsserializer.StringEncodingWindows1252
+		}
+	}
+
+	// Sum commands: one sub-read of the upload info
+	subRead := adsrequests.BuildReadRequest(uint32(types.ADSReservedIndexGroupSymbolUploadInfo2), 0, uploadInfoSize)
+	_, err = c.probe(AdsCommandRequest{
+		Command:    types.ADSCommandReadWrite,
+		TargetPort: port,
+		Data:       adsrequests.BuildReadWriteRequest(uint32(types.ADSReservedIndexGroupSumCommandRead), 1, 4+uploadInfoSize, subRead),
+	})
+	if err != nil && !errors.Is(err, adserrors.ErrAdsError) {
+		return nil, fmt.Errorf("ProbeTarget: sum commands: %w", err)
+	}
+	info.SumCommands = err == nil
+
+	// Symbol handles
+	data, err = c.probe(AdsCommandRequest{
+		Command:    types.ADSCommandReadWrite,
+		TargetPort: port,
+		Data:       adsrequests.BuildReadWriteRequestWithNullTerminator(uint32(types.ADSReservedIndexGroupSymbolHandleByName), 0, 4, []byte(probeSymbolName)),
+	})
+	if err != nil && !errors.Is(err, adserrors.ErrAdsError) {
+		return nil, fmt.Errorf("ProbeTarget: handles: %w", err)
+	}
+	info.Handles = !isAdsErrorCode(err, 1793, 1794) // service not supported, invalid index group
+	if err == nil && len(data) >= 4 {
+		if err := c.WriteRaw(port, uint32(types.ADSReservedIndexGroupSymbolReleaseHandle), 0, data[0:4]); err != nil {
+			c.logger.Debug("ProbeTarget: Failed to release probe handle", "error", err)
+		}
+	}
+
+	// Method calls go through symbol handles and exist since TwinCAT 3. There is
+	// no call without side effects to probe them directly.
+	info.RPC = info.Handles && info.Version >= 3
+
+	info.ProbedAt = time.Now()
+	c.targetInfoMutex.Lock()
+	c.targetInfo = info
+	c.targetInfoMutex.Unlock()
+
+	// The state poller does not need to find out on its own
+	supported := info.ExtendedState
+	c.extendedStateMutex.Lock()
+	c.extendedStateSupported = &supported
+	c.extendedStateMutex.Unlock()
+
+	c.logger.Info("ProbeTarget: Target probed",
+		"version", fmt.Sprintf("%d.%d.%d", info.Version, info.Revision, info.Build),
+		"extendedState", info.ExtendedState,
+		"sumCommands", info.SumCommands,
+		"handles", info.Handles,
+		"is64Bit", info.Is64Bit,
+		"stringEncoding", info.StringEncoding)
+	result := *info
+	return &result, nil
+}
+
+// probe sends req and returns the payload of the response. A request that is
+// not answered returns the send error; an ADS error is returned as is.
+func (c *Client) probe(req AdsCommandRequest) ([]byte, error) {
+	response, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := adsheader.StripAdsHeader(response)
+	if err != nil && !errors.Is(err, adserrors.ErrAdsError) {
+		// Answered but malformed, treat the service as not supported
+		return nil, fmt.Errorf("%w: %v", adserrors.ErrAdsError, err)
+	}
+	return data, err
+}
+
+// sumCommandsSupported reports whether sum commands may be used. It is true
+// unless the probe found that the target rejects them.
+func (c *Client) sumCommandsSupported() bool {
+	c.targetInfoMutex.RLock()
+	defer c.targetInfoMutex.RUnlock()
+	return c.targetInfo == nil || c.targetInfo.SumCommands
+}
+
+// serializerOptions returns the options ReadValue and WriteValue convert
+// values with. STRING data is UTF-8 unless the probe found an older runtime.
+func (c *Client) serializerOptions() adsserializer.Options {
+	opts := adsserializer.Options{
+		TruncateStrings: c.settings.TruncateStrings,
+		EnumMode:        c.settings.EnumMode,
+		TimeAliases:     c.settings.TimeAliases,
+	}
+	c.targetInfoMutex.RLock()
+	defer c.targetInfoMutex.RUnlock()
+	if c.targetInfo != nil {
+		opts.StringEncoding = c.targetInfo.StringEncoding
+	}
+	return opts
+}
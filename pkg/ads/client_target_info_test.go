@@ -0,0 +1,176 @@
+package ads
+
+import (
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+
+	adserrors "github.com/jarmocluyse/ads-go/pkg/ads/ads-errors"
+	adsserializer "github.com/jarmocluyse/ads-go/pkg/ads/ads-serializer"
+	"github.com/jarmocluyse/ads-go/pkg/ads/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// targetInfoRouter answers the probes of a TwinCAT 3.1.4026 target without
+// sum command support. Sum commands and single AddNotifications are counted.
+func targetInfoRouter(t *testing.T, sumRequests, addNotifications *atomic.Int32) *fakeRouter {
+	return newFakeRouter(t, func(cmd types.ADSCommand, port uint16, data []byte) []byte {
+		switch cmd {
+		case types.ADSCommandRead:
+			indexGroup := binary.LittleEndian.Uint32(data[0:4])
+			switch {
+			case port == uint16(types.ADSReservedPortSystemService) && indexGroup == 240:
+				resp := make([]byte, 8+16)
+				binary.LittleEndian.PutUint32(resp[4:8], 16)
+				binary.LittleEndian.PutUint16(resp[8:10], uint16(types.ADSStateRun))
+				resp[8+6], resp[8+7] = 3, 1 // version 3.1
+				binary.LittleEndian.PutUint16(resp[8+8:], 4026)
+				resp[8+10], resp[8+11] = 2, 4 // platform, os type
+				binary.LittleEndian.PutUint16(resp[8+12:], uint16(types.ADSSystemServiceStateFlagDataFolderSupport))
+				return resp
+			case port == 851 && indexGroup == uint32(types.ADSReservedIndexGroupSymbolUploadInfo2):
+				resp := make([]byte, 8+uploadInfoSize)
+				binary.LittleEndian.PutUint32(resp[4:8], uploadInfoSize)
+				flags := types.ADSUploadInfoFlagIs64BitPlatform | types.ADSUploadInfoFlagUtf8EncodedStringData
+				binary.LittleEndian.PutUint32(resp[8+uploadInfoFlagsOffset:], uint32(flags))
+				return resp
+			}
+		case types.ADSCommandReadWrite:
+			indexGroup, _ := sumCommandHeader(data)
+			resp := make([]byte, 8)
+			switch {
+			case indexGroup >= types.ADSReservedIndexGroupSumCommandRead && indexGroup <= types.ADSReservedIndexGroupSumCommandDelDevNote:
+				sumRequests.Add(1)
+				binary.LittleEndian.PutUint32(resp[0:4], 1793) // service not supported
+				return resp
+			case indexGroup == types.ADSReservedIndexGroupSymbolHandleByName:
+				binary.LittleEndian.PutUint32(resp[0:4], 1808) // symbol not found
+				return resp
+			}
+		case types.ADSCommandAddNotification:
+			resp := make([]byte, 8)
+			binary.LittleEndian.PutUint32(resp[4:8], uint32(100+addNotifications.Add(1)))
+			return resp
+		}
+		return defaultFakeHandler(cmd, port, data)
+	})
+}
+
+func TestTargetInfo(t *testing.T) {
+	var sumRequests, addNotifications atomic.Int32
+	router := targetInfoRouter(t, &sumRequests, &addNotifications)
+	c := NewClient(router.settings(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	assert.Nil(t, c.TargetInfo(), "not probed before Connect")
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer func() { _ = c.Disconnect() }()
+
+	info := c.TargetInfo()
+	if info == nil {
+		t.Fatalf("Expected target info after Connect")
+	}
+	assert.Equal(t, "FakeRouter", info.DeviceInfo.DeviceName)
+	assert.Equal(t, uint8(3), info.Version)
+	assert.Equal(t, uint8(1), info.Revision)
+	assert.Equal(t, uint16(4026), info.Build)
+	assert.Equal(t, uint8(2), info.Platform)
+	assert.Equal(t, uint8(4), info.OsType)
+	assert.Equal(t, types.ADSSystemServiceStateFlagDataFolderSupport, info.SystemFlags)
+	assert.True(t, info.Is64Bit)
+	assert.True(t, info.Utf8Strings)
+	assert.Equal(t, adsserializer.StringEncodingUTF8, info.StringEncoding)
+	assert.True(t, info.ExtendedState)
+	assert.False(t, info.SumCommands)
+	assert.True(t, info.Handles)
+	assert.True(t, info.RPC)
+	assert.False(t, info.ProbedAt.IsZero())
+	assert.Equal(t, info, c.Debug().TargetInfo)
+	if supported := c.Debug().ExtendedStateSupported; assert.NotNil(t, supported) {
+		assert.True(t, *supported, "the state poller uses the probe result")
+	}
+	assert.Equal(t, int32(1), sumRequests.Load(), "only the probe sends a sum command")
+
+	// Without sum commands SubscribeMany subscribes one by one right away
+	callback := func(SubscriptionData) {}
+	results, err := c.SubscribeMany([]SubscriptionRequest{
+		{Port: 851, IndexGroup: 0x4020, IndexOffset: 0, Size: 4, Callback: callback},
+		{Port: 851, IndexGroup: 0x4020, IndexOffset: 4, Size: 4, Callback: callback},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Len(t, results, 2)
+	assert.Equal(t, int32(2), addNotifications.Load())
+	if err := c.UnsubscribeAll(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, int32(1), sumRequests.Load(), "no sum command is tried")
+
+	// The returned info is a copy
+	info.SumCommands = true
+	assert.False(t, c.TargetInfo().SumCommands)
+}
+
+func TestTargetInfoPortNotFound(t *testing.T) {
+	// The router rejects the PLC port in the AMS header, e.g. while no PLC
+	// project is running
+	router := newFakeRouter(t, nil)
+	router.setPortError(851, 6) // target port not found
+	c := NewClient(router.settings(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer func() { _ = c.Disconnect() }()
+
+	info := c.TargetInfo()
+	if info == nil {
+		t.Fatalf("Expected target info after Connect")
+	}
+	assert.False(t, info.SumCommands)
+	assert.True(t, info.Handles, "only service errors mark handles as not supported")
+	assert.Zero(t, info.UploadFlags)
+	assert.NotNil(t, c.Debug().ExtendedStateSupported, "the probe result is cached")
+
+	_, err := c.ReadRaw(851, 0x4020, 0, 4)
+	code, ok := adserrors.ErrorCode(err)
+	assert.True(t, ok, "AMS header errors carry the code")
+	assert.Equal(t, uint32(6), code)
+	assert.ErrorIs(t, err, adserrors.ErrAdsError)
+}
+
+func TestTargetInfoStringEncoding(t *testing.T) {
+	// An older runtime reports upload flags without UTF-8 strings
+	router := newFakeRouter(t, func(cmd types.ADSCommand, port uint16, data []byte) []byte {
+		if cmd == types.ADSCommandRead && binary.LittleEndian.Uint32(data[0:4]) == uint32(types.ADSReservedIndexGroupSymbolUploadInfo2) {
+			resp := make([]byte, 8+uploadInfoSize)
+			binary.LittleEndian.PutUint32(resp[4:8], uploadInfoSize)
+			return resp
+		}
+		return defaultFakeHandler(cmd, port, data)
+	})
+	settings := router.settings()
+	settings.TruncateStrings = true
+	c := NewClient(settings, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	assert.Equal(t, adsserializer.StringEncodingUTF8, c.serializerOptions().StringEncoding, "UTF-8 before the probe")
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer func() { _ = c.Disconnect() }()
+
+	assert.Equal(t, adsserializer.StringEncodingWindows1252, c.TargetInfo().StringEncoding)
+	dataType := types.AdsDataType{DataType: types.ADST_STRING, Size: 5}
+	value, err := c.convertBufferToValue([]byte{'G', 'r', 0xF6, 0xDF, 'e'}, dataType)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, "Größe", value)
+
+	data, err := c.convertValueToBuffer("Größe", dataType)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, []byte{'G', 'r', 0xF6, 0xDF, 0x00}, data, "truncated to STRING(4)")
+}
@@ -7,32 +7,37 @@ import (
 	"github.com/jarmocluyse/ads-go/pkg/ads/types"
 )
 
+// WriteValue converts value to the data type of the variable path and writes
+// it. References and dereferenced pointers ("GVL.pAxis^") are written through
+// a variable handle that is created and released on every call, which costs
+// three round trips instead of one.
 func (c *Client) WriteValue(port uint16, path string, value any) error {
 	c.logger.Debug("WriteValue: Writing value", "path", path)
 
 	// Check if system is in Run mode before writing
-	if err := c.checkStateForOperation("WriteValue"); err != nil {
+	if err := c.checkStateForOperation("WriteValue", port); err != nil {
 		return err
 	}
 
-	symbol, err := c.GetSymbol(port, path)
+	target, err := c.resolveVariable(port, path)
 	if err != nil {
-		return fmt.Errorf("WriteValue: failed to get symbol: %w", err)
+		return fmt.Errorf("WriteValue: %w", err)
 	}
 
-	dataType, err := c.GetDataType(symbol.Type, port)
-	if err != nil {
-		return fmt.Errorf("WriteValue: failed to get data type: %w", err)
-	}
-
-	data, err := c.convertValueToBuffer(value, dataType)
+	data, err := c.convertValueToBuffer(value, target.dataType)
 	if err != nil {
 		return fmt.Errorf("WriteValue: failed to convert value to buffer: %w", err)
 	}
-	err = c.WriteRaw(port, symbol.IndexGroup, symbol.IndexOffset, data)
+	if target.dereference {
+		if err := c.writeByHandle(port, path, data); err != nil {
+			return fmt.Errorf("WriteValue: %w", err)
+		}
+		return nil
+	}
+	err = c.WriteRaw(port, target.symbol.IndexGroup, target.symbol.IndexOffset, data)
 	return err
 }
 
 func (c *Client) convertValueToBuffer(value any, dataType types.AdsDataType, isArrayItem ...bool) ([]byte, error) {
-	return adsserializer.Serialize(value, dataType, isArrayItem...)
+	return adsserializer.SerializeWithOptions(value, dataType, c.serializerOptions(), isArrayItem...)
 }
@@ -248,6 +248,11 @@ Control the PLC runtime state:
 		log.Fatal(err)
 	}
 
+	// Stop, reset and start a single PLC runtime (waits for the state)
+	err = client.StopPlc(852)
+	err = client.ResetPlcCold(852)
+	err = client.StartPlc(852)
+
 	// Read current system state
 	state, err := client.ReadTcSystemState()
 	if err != nil {
@@ -1,7 +1,62 @@
 package ads
 
-import "errors"
+import (
+	"errors"
+
+	adserrors "github.com/jarmocluyse/ads-go/pkg/ads/ads-errors"
+)
 
 // ErrNotConnected is returned when an operation is attempted without an
 // active connection. Callers can match on this with errors.Is.
 var ErrNotConnected = errors.New("not connected")
+
+// ErrTimeout is returned when the target did not answer a command within
+// ClientSettings.Timeout.
+var ErrTimeout = errors.New("timeout waiting for response")
+
+// ErrClientClosed is returned when an operation is attempted on a client that
+// was shut down. A closed client cannot be reconnected.
+var ErrClientClosed = errors.New("client closed")
+
+// ErrSubscriptionClosed is the terminal error of a channel subscription that
+// was removed with Unsubscribe, UnsubscribeAll or Disconnect.
+var ErrSubscriptionClosed = errors.New("subscription closed")
+
+// ErrPlcNotRunning is returned by ReadValue and WriteValue when the PLC
+// runtime on the target port is not in Run (see ClientSettings.PlcStateGate).
+var ErrPlcNotRunning = errors.New("PLC runtime not running")
+
+// ErrPlcControlTimeout is returned by StartPlc, StopPlc, ResetPlcCold and
+// ResetPlcOrigin when the runtime did not reach the requested state in time.
+var ErrPlcControlTimeout = errors.New("PLC runtime control timed out")
+
+// ErrNoActiveController is returned by RedundantClient when neither
+// controller of the redundancy pair was reachable.
+var ErrNoActiveController = errors.New("no active controller")
+
+// ErrSymbolVersionChanged is passed to OnInvalidated when the symbol version
+// of the target port changed, e.g. after a PLC program download.
+var ErrSymbolVersionChanged = errors.New("symbol version changed")
+
+// ErrUnknownNotificationHandles is passed to OnError when the target keeps
+// sending notifications with handles the client does not know.
+var ErrUnknownNotificationHandles = errors.New("unknown notification handles")
+
+// isAdsErrorCode reports whether err is one of the ADS error codes.
+func isAdsErrorCode(err error, codes ...uint32) bool {
+	errorCode, ok := adserrors.ErrorCode(err)
+	if !ok {
+		return false
+	}
+	for _, code := range codes {
+		if errorCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// isAdsNotFound reports whether err is ADS error 1804 (not found).
+func isAdsNotFound(err error) bool {
+	return isAdsErrorCode(err, 1804)
+}
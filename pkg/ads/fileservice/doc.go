@@ -0,0 +1,51 @@
+// Package fileservice provides remote file access over the ADS system service.
+//
+// TwinCAT exposes file open, read, write, close, delete and directory search
+// on the system service port (10000) through dedicated index groups. FS wraps
+// these as an fs.FS rooted at a directory on the target, so the standard
+// library helpers (fs.ReadFile, fs.WalkDir, fs.Glob, ...) work on it.
+//
+// # Usage
+//
+//	client := ads.NewClient(settings, logger)
+//	if err := client.Connect(); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	fsys := fileservice.New(client, `C:\TwinCAT\3.1\Boot`)
+//	entries, err := fsys.ReadDir(".")
+//	data, err := fsys.ReadFile("Log/app.log")
+//	err = fsys.WriteFile("Recipes/recipe1.csv", csv)
+//	err = fsys.Remove("Recipes/old.csv")
+//
+// Names are slash-separated and relative to the root, as required by fs.FS.
+// They are converted to Windows paths on the target. Files missing on the
+// target are reported with errors wrapping fs.ErrNotExist.
+//
+// # Index Groups
+//
+// All commands go to port 10000:
+//   - 120 (FOPEN): ReadWrite, offset = open mode | path type << 16, write = null-terminated path, read = 4 byte handle
+//   - 121 (FCLOSE): Write, offset = handle
+//   - 122 (FREAD): Read, offset = handle, read = up to the requested length
+//   - 123 (FWRITE): Write, offset = handle, write = data
+//   - 131 (FDELETE): ReadWrite, offset = path type << 16, write = null-terminated path
+//   - 133 (FFILEFIND): ReadWrite, offset = path type << 16 (first) or the find handle (next), read = 324 byte find entry
+//
+// Paths use the generic path type (1), so absolute target paths are used as-is.
+//
+// # Find Entry Format
+//
+// A find entry is a WIN32_FIND_DATA structure prefixed with the find handle
+// (all fields little-endian):
+//   - Bytes 0-3:     Find handle (uint32) - passed as offset to find the next entry
+//   - Bytes 4-7:     File attributes (uint32) - 0x10 = directory, 0x01 = read-only
+//   - Bytes 8-31:    Creation, last access and last write time (FILETIME, 100ns since 1601)
+//   - Bytes 32-39:   File size high and low (uint32 each)
+//   - Bytes 40-47:   Reserved
+//   - Bytes 48-307:  File name (null-terminated, 260 bytes)
+//   - Bytes 308-321: Alternate 8.3 file name (14 bytes)
+//
+// The search ends with ADS error 1804 (not found), which also closes the find
+// handle on the target.
+package fileservice
@@ -0,0 +1,190 @@
+package fileservice
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/fs"
+	"time"
+)
+
+// File attributes of a find entry.
+const (
+	attributeReadOnly  uint32 = 0x01
+	attributeDirectory uint32 = 0x10
+)
+
+// filetimeEpoch is the FILETIME epoch (1601-01-01) as Unix time in seconds.
+const filetimeEpoch = -11644473600
+
+// file is a file opened for reading.
+type file struct {
+	fsys   *FS
+	name   string
+	info   fs.FileInfo
+	handle uint32
+	eof    bool
+	closed bool
+}
+
+// Stat returns the file info from when the file was opened.
+func (f *file) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+// Read reads up to len(p) bytes from the target.
+func (f *file) Read(p []byte) (int, error) {
+	if f.closed {
+		return 0, &fs.PathError{Op: "read", Path: f.name, Err: fs.ErrClosed}
+	}
+	if f.eof {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	data, err := f.fsys.read(f.handle, uint32(min(len(p), chunkSize)))
+	if err != nil {
+		return 0, &fs.PathError{Op: "read", Path: f.name, Err: err}
+	}
+	if len(data) == 0 {
+		f.eof = true
+		return 0, io.EOF
+	}
+	return copy(p, data), nil
+}
+
+// Close releases the file handle on the target.
+func (f *file) Close() error {
+	if f.closed {
+		return &fs.PathError{Op: "close", Path: f.name, Err: fs.ErrClosed}
+	}
+	f.closed = true
+	if err := f.fsys.close(f.handle); err != nil {
+		return &fs.PathError{Op: "close", Path: f.name, Err: err}
+	}
+	return nil
+}
+
+// dirFile is an opened directory. The entries are read on the first ReadDir.
+type dirFile struct {
+	fsys    *FS
+	name    string
+	info    fs.FileInfo
+	entries []fs.DirEntry
+	read    bool
+	closed  bool
+}
+
+// Stat returns the directory info.
+func (d *dirFile) Stat() (fs.FileInfo, error) {
+	return d.info, nil
+}
+
+// Read fails, directories cannot be read as a byte stream.
+func (d *dirFile) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: fmt.Errorf("is a directory")}
+}
+
+// ReadDir returns the next n entries, or all remaining entries if n <= 0.
+func (d *dirFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	if d.closed {
+		return nil, &fs.PathError{Op: "readdir", Path: d.name, Err: fs.ErrClosed}
+	}
+	if !d.read {
+		entries, err := d.fsys.ReadDir(d.name)
+		if err != nil {
+			return nil, err
+		}
+		d.entries = entries
+		d.read = true
+	}
+
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(d.entries))
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
+
+// Close closes the directory. No handle is held on the target.
+func (d *dirFile) Close() error {
+	if d.closed {
+		return &fs.PathError{Op: "close", Path: d.name, Err: fs.ErrClosed}
+	}
+	d.closed = true
+	return nil
+}
+
+// fileInfo is the fs.FileInfo of a find entry.
+type fileInfo struct {
+	name       string
+	size       int64
+	attributes uint32
+	modTime    time.Time
+}
+
+func (i *fileInfo) Name() string       { return i.name }
+func (i *fileInfo) Size() int64        { return i.size }
+func (i *fileInfo) ModTime() time.Time { return i.modTime }
+func (i *fileInfo) IsDir() bool        { return i.attributes&attributeDirectory != 0 }
+func (i *fileInfo) Sys() any           { return i.attributes }
+
+// Mode derives the permissions from the read-only attribute.
+func (i *fileInfo) Mode() fs.FileMode {
+	mode := fs.FileMode(0o666)
+	if i.attributes&attributeReadOnly != 0 {
+		mode = 0o444
+	}
+	if i.IsDir() {
+		mode |= fs.ModeDir | 0o111
+	}
+	return mode
+}
+
+// findEntry is a parsed FFILEFIND response.
+type findEntry struct {
+	handle uint32
+	info   fileInfo
+}
+
+// parseFindEntry parses a find entry (see the package documentation).
+func parseFindEntry(data []byte) (findEntry, error) {
+	if len(data) < 308 {
+		return findEntry{}, fmt.Errorf("invalid find entry length: %d", len(data))
+	}
+	name := data[48:308]
+	if end := bytes.IndexByte(name, 0); end >= 0 {
+		name = name[:end]
+	}
+	return findEntry{
+		handle: leUint32(data[0:4]),
+		info: fileInfo{
+			name:       string(name),
+			size:       int64(leUint32(data[32:36]))<<32 | int64(leUint32(data[36:40])),
+			attributes: leUint32(data[4:8]),
+			modTime:    filetimeToTime(binary.LittleEndian.Uint64(data[24:32])),
+		},
+	}, nil
+}
+
+// filetimeToTime converts a FILETIME (100ns intervals since 1601) to time.Time.
+func filetimeToTime(ft uint64) time.Time {
+	if ft == 0 {
+		return time.Time{}
+	}
+	return time.Unix(filetimeEpoch+int64(ft/1e7), int64(ft%1e7)*100).UTC()
+}
+
+func leUint32(b []byte) uint32 {
+	return binary.LittleEndian.Uint32(b)
+}
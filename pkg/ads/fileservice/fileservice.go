@@ -0,0 +1,284 @@
+package fileservice
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	adserrors "github.com/jarmocluyse/ads-go/pkg/ads/ads-errors"
+)
+
+// SystemServicePort is the ADS port of the TwinCAT system service.
+const SystemServicePort uint16 = 10000
+
+// Index groups of the system service file commands.
+const (
+	IndexGroupOpen   uint32 = 120
+	IndexGroupClose  uint32 = 121
+	IndexGroupRead   uint32 = 122
+	IndexGroupWrite  uint32 = 123
+	IndexGroupDelete uint32 = 131
+	IndexGroupFind   uint32 = 133
+)
+
+// Open mode flags of FOPEN.
+const (
+	OpenRead   uint32 = 1 << 0
+	OpenWrite  uint32 = 1 << 1
+	OpenAppend uint32 = 1 << 2
+	OpenPlus   uint32 = 1 << 3
+	OpenBinary uint32 = 1 << 4
+	OpenText   uint32 = 1 << 5
+)
+
+// pathGeneric selects the generic path type: paths are used as-is.
+const pathGeneric uint32 = 1 << 16
+
+// chunkSize is the maximum number of bytes read or written per command.
+const chunkSize = 16 * 1024
+
+// findEntrySize is the size of a find entry (handle + WIN32_FIND_DATA).
+const findEntrySize = 324
+
+// adsNotFound is ADS error 1804 (not found).
+const adsNotFound = 1804
+
+// RawClient is the part of *ads.Client used by FS.
+type RawClient interface {
+	ReadRaw(port uint16, indexGroup uint32, indexOffset uint32, size uint32) ([]byte, error)
+	WriteRaw(port uint16, indexGroup uint32, indexOffset uint32, data []byte) error
+	ReadWriteRaw(port uint16, indexGroup uint32, indexOffset uint32, readLength uint32, writeData []byte) ([]byte, error)
+}
+
+// FS is a view on a directory of the target file system. It implements
+// fs.FS, fs.ReadFileFS, fs.ReadDirFS and fs.StatFS, plus WriteFile and Remove.
+type FS struct {
+	client RawClient
+	root   string
+}
+
+var (
+	_ fs.ReadFileFS = (*FS)(nil)
+	_ fs.ReadDirFS  = (*FS)(nil)
+	_ fs.StatFS     = (*FS)(nil)
+)
+
+// New returns an FS rooted at root, a directory on the target
+// (e.g. `C:\TwinCAT\3.1\Boot`).
+func New(client RawClient, root string) *FS {
+	return &FS{client: client, root: strings.TrimRight(root, `\/`)}
+}
+
+// targetPath converts a slash-separated name to a path on the target.
+func (f *FS) targetPath(name string) string {
+	if name == "." {
+		return f.root
+	}
+	name = strings.ReplaceAll(name, "/", `\`)
+	if f.root == "" {
+		return name
+	}
+	return f.root + `\` + name
+}
+
+// Open opens the named file or directory for reading.
+func (f *FS) Open(name string) (fs.File, error) {
+	info, err := f.Stat(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: unwrapPathError(err)}
+	}
+	if info.IsDir() {
+		return &dirFile{fsys: f, name: name, info: info}, nil
+	}
+
+	handle, err := f.open(name, OpenRead|OpenBinary)
+	if err != nil {
+		return nil, err
+	}
+	return &file{fsys: f, name: name, info: info, handle: handle}, nil
+}
+
+// ReadFile reads the named file.
+func (f *FS) ReadFile(name string) ([]byte, error) {
+	handle, err := f.open(name, OpenRead|OpenBinary)
+	if err != nil {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: unwrapPathError(err)}
+	}
+	defer func() { _ = f.close(handle) }()
+
+	var data []byte
+	for {
+		chunk, err := f.read(handle, chunkSize)
+		if err != nil {
+			return nil, &fs.PathError{Op: "read", Path: name, Err: err}
+		}
+		data = append(data, chunk...)
+		if len(chunk) < chunkSize {
+			return data, nil
+		}
+	}
+}
+
+// WriteFile writes data to the named file, creating or truncating it.
+// The directory must exist.
+func (f *FS) WriteFile(name string, data []byte) error {
+	handle, err := f.open(name, OpenWrite|OpenBinary)
+	if err != nil {
+		return &fs.PathError{Op: "write", Path: name, Err: unwrapPathError(err)}
+	}
+
+	for len(data) > 0 {
+		n := min(len(data), chunkSize)
+		if err := f.client.WriteRaw(SystemServicePort, IndexGroupWrite, handle, data[:n]); err != nil {
+			_ = f.close(handle)
+			return &fs.PathError{Op: "write", Path: name, Err: err}
+		}
+		data = data[n:]
+	}
+
+	if err := f.close(handle); err != nil {
+		return &fs.PathError{Op: "write", Path: name, Err: err}
+	}
+	return nil
+}
+
+// Remove deletes the named file.
+func (f *FS) Remove(name string) error {
+	if !validName(name) || name == "." {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrInvalid}
+	}
+	if _, err := f.client.ReadWriteRaw(SystemServicePort, IndexGroupDelete, pathGeneric, 0, []byte(f.targetPath(name))); err != nil {
+		return &fs.PathError{Op: "remove", Path: name, Err: mapError(err)}
+	}
+	return nil
+}
+
+// Stat returns the file info of the named file or directory.
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+	if !validName(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return &fileInfo{name: ".", attributes: attributeDirectory}, nil
+	}
+
+	entry, err := f.findFirst(f.targetPath(name))
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+	}
+	// Only the first entry is needed, release the find handle
+	_ = f.close(entry.handle)
+
+	info := entry.info
+	info.name = path.Base(name)
+	return &info, nil
+}
+
+// ReadDir reads the named directory and returns its entries sorted by name.
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !validName(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	if name != "." {
+		info, err := f.Stat(name)
+		if err != nil {
+			return nil, &fs.PathError{Op: "readdir", Path: name, Err: unwrapPathError(err)}
+		}
+		if !info.IsDir() {
+			return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+		}
+	}
+
+	var entries []fs.DirEntry
+	entry, err := f.findFirst(f.targetPath(name) + `\*`)
+	var handle uint32
+	searching := err == nil
+	for err == nil {
+		handle = entry.handle
+		if entry.info.name != "." && entry.info.name != ".." {
+			info := entry.info
+			entries = append(entries, fs.FileInfoToDirEntry(&info))
+		}
+		entry, err = f.findNext(handle)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		// The target only releases the search when it ran to the end
+		if searching {
+			_ = f.close(handle)
+		}
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// open opens name with mode and returns the file handle.
+func (f *FS) open(name string, mode uint32) (uint32, error) {
+	if !validName(name) || name == "." {
+		return 0, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	data, err := f.client.ReadWriteRaw(SystemServicePort, IndexGroupOpen, mode|pathGeneric, 4, []byte(f.targetPath(name)))
+	if err != nil {
+		return 0, &fs.PathError{Op: "open", Path: name, Err: mapError(err)}
+	}
+	if len(data) < 4 {
+		return 0, &fs.PathError{Op: "open", Path: name, Err: fmt.Errorf("invalid handle length: %d", len(data))}
+	}
+	return leUint32(data), nil
+}
+
+// read reads up to size bytes from handle. An empty result means end of file.
+func (f *FS) read(handle uint32, size uint32) ([]byte, error) {
+	return f.client.ReadRaw(SystemServicePort, IndexGroupRead, handle, size)
+}
+
+// close releases a file or find handle.
+func (f *FS) close(handle uint32) error {
+	return f.client.WriteRaw(SystemServicePort, IndexGroupClose, handle, nil)
+}
+
+// findFirst starts a search for pattern (a path, optionally with wildcards).
+func (f *FS) findFirst(pattern string) (findEntry, error) {
+	data, err := f.client.ReadWriteRaw(SystemServicePort, IndexGroupFind, pathGeneric, findEntrySize, []byte(pattern))
+	if err != nil {
+		return findEntry{}, mapError(err)
+	}
+	return parseFindEntry(data)
+}
+
+// findNext returns the next entry of the search with handle.
+func (f *FS) findNext(handle uint32) (findEntry, error) {
+	data, err := f.client.ReadWriteRaw(SystemServicePort, IndexGroupFind, handle, findEntrySize, nil)
+	if err != nil {
+		return findEntry{}, mapError(err)
+	}
+	return parseFindEntry(data)
+}
+
+// validName reports whether name is a valid fs.FS name that does not use
+// Windows separators or drive letters.
+func validName(name string) bool {
+	return fs.ValidPath(name) && !strings.ContainsAny(name, `\:`)
+}
+
+// mapError wraps ADS "not found" errors with fs.ErrNotExist.
+func mapError(err error) error {
+	if code, ok := adserrors.ErrorCode(err); ok && code == adsNotFound {
+		return fmt.Errorf("%w (%w)", fs.ErrNotExist, err)
+	}
+	return err
+}
+
+// unwrapPathError returns the underlying error of a *fs.PathError so it can
+// be wrapped again with the operation of the caller.
+func unwrapPathError(err error) error {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Err
+	}
+	return err
+}
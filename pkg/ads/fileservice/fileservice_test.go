@@ -0,0 +1,240 @@
+package fileservice
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/jarmocluyse/ads-go/pkg/ads"
+	adserrors "github.com/jarmocluyse/ads-go/pkg/ads/ads-errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ RawClient = (*ads.Client)(nil)
+
+// fakeTarget implements the system service file commands on an in-memory
+// file system. Directories are entries with a nil content.
+type fakeTarget struct {
+	mu      sync.Mutex
+	files   map[string][]byte
+	modTime time.Time
+	next    uint32
+	open    map[uint32]*fakeHandle
+	finds   map[uint32][]string
+	closes  int
+	findErr error // returned by find next if set
+}
+
+type fakeHandle struct {
+	path string
+	pos  int
+}
+
+func newFakeTarget(files map[string]string) *fakeTarget {
+	t := &fakeTarget{
+		files:   map[string][]byte{`C:\Data`: nil},
+		modTime: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		open:    make(map[uint32]*fakeHandle),
+		finds:   make(map[uint32][]string),
+	}
+	for name, content := range files {
+		if strings.HasSuffix(name, `\`) {
+			t.files[strings.TrimSuffix(name, `\`)] = nil
+		} else {
+			t.files[name] = []byte(content)
+		}
+	}
+	return t
+}
+
+func notFound() error {
+	return &adserrors.AdsError{Code: 1804}
+}
+
+func (t *fakeTarget) handle() uint32 {
+	t.next++
+	return t.next
+}
+
+func (t *fakeTarget) entry(handle uint32, path string) []byte {
+	data := make([]byte, findEntrySize)
+	binary.LittleEndian.PutUint32(data[0:4], handle)
+	content, ok := t.files[path]
+	if ok && content == nil {
+		binary.LittleEndian.PutUint32(data[4:8], attributeDirectory)
+	}
+	ft := uint64(t.modTime.Unix()-filetimeEpoch) * 1e7
+	binary.LittleEndian.PutUint64(data[24:32], ft)
+	binary.LittleEndian.PutUint32(data[36:40], uint32(len(content)))
+	copy(data[48:308], path[strings.LastIndex(path, `\`)+1:])
+	return data
+}
+
+func (t *fakeTarget) ReadRaw(port uint16, indexGroup uint32, indexOffset uint32, size uint32) ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	h := t.open[indexOffset]
+	if port != SystemServicePort || indexGroup != IndexGroupRead || h == nil {
+		return nil, fmt.Errorf("%w: unexpected read", adserrors.ErrAdsError)
+	}
+	content := t.files[h.path]
+	n := min(int(size), len(content)-h.pos)
+	data := append([]byte(nil), content[h.pos:h.pos+n]...)
+	h.pos += n
+	return data, nil
+}
+
+func (t *fakeTarget) WriteRaw(port uint16, indexGroup uint32, indexOffset uint32, data []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch indexGroup {
+	case IndexGroupWrite:
+		h := t.open[indexOffset]
+		t.files[h.path] = append(t.files[h.path], data...)
+	case IndexGroupClose:
+		t.closes++
+		delete(t.open, indexOffset)
+		delete(t.finds, indexOffset)
+	default:
+		return fmt.Errorf("%w: unexpected write", adserrors.ErrAdsError)
+	}
+	return nil
+}
+
+func (t *fakeTarget) ReadWriteRaw(port uint16, indexGroup uint32, indexOffset uint32, readLength uint32, writeData []byte) ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	path := string(writeData)
+	switch indexGroup {
+	case IndexGroupOpen:
+		content, ok := t.files[path]
+		switch {
+		case indexOffset&OpenWrite != 0:
+			parent := path[:strings.LastIndex(path, `\`)]
+			if dir, ok := t.files[parent]; !ok || dir != nil {
+				return nil, notFound()
+			}
+			t.files[path] = []byte{}
+		case !ok || content == nil:
+			return nil, notFound()
+		}
+		handle := t.handle()
+		t.open[handle] = &fakeHandle{path: path}
+		return binary.LittleEndian.AppendUint32(nil, handle), nil
+	case IndexGroupDelete:
+		if content, ok := t.files[path]; !ok || content == nil {
+			return nil, notFound()
+		}
+		delete(t.files, path)
+		return nil, nil
+	case IndexGroupFind:
+		if indexOffset != pathGeneric {
+			if t.findErr != nil {
+				return nil, t.findErr
+			}
+			matches := t.finds[indexOffset]
+			if len(matches) == 0 {
+				delete(t.finds, indexOffset)
+				return nil, notFound()
+			}
+			t.finds[indexOffset] = matches[1:]
+			return t.entry(indexOffset, matches[0]), nil
+		}
+		var matches []string
+		if dir, ok := strings.CutSuffix(path, `\*`); ok {
+			matches = []string{dir + `\.`, dir + `\..`}
+			for name := range t.files {
+				if parent, _, found := strings.Cut(strings.TrimPrefix(name, dir+`\`), `\`); name != dir && strings.HasPrefix(name, dir+`\`) && !found && parent != "" {
+					matches = append(matches, name)
+				}
+			}
+			sort.Strings(matches)
+		} else if _, ok := t.files[path]; ok {
+			matches = []string{path}
+		}
+		if len(matches) == 0 {
+			return nil, notFound()
+		}
+		handle := t.handle()
+		t.finds[handle] = matches[1:]
+		return t.entry(handle, matches[0]), nil
+	}
+	return nil, fmt.Errorf("%w: unexpected read/write", adserrors.ErrAdsError)
+}
+
+func TestFS(t *testing.T) {
+	big := strings.Repeat("0123456789", 5000) // more than one chunk
+	target := newFakeTarget(map[string]string{
+		`C:\Data\app.log`:         "line 1\nline 2\n",
+		`C:\Data\Recipes\`:        "",
+		`C:\Data\Recipes\a.csv`:   "a;1",
+		`C:\Data\Recipes\big.csv`: big,
+		`C:\Data\Recipes\Empty\`:  "",
+	})
+	fsys := New(target, `C:\Data\`)
+
+	if err := fstest.TestFS(fsys, "app.log", "Recipes/a.csv", "Recipes/big.csv", "Recipes/Empty"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := fsys.ReadFile("Recipes/big.csv")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, big, string(data))
+
+	info, err := fsys.Stat("app.log")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, int64(14), info.Size())
+	assert.Equal(t, target.modTime, info.ModTime())
+	assert.Empty(t, target.open, "file handles are closed")
+	assert.Empty(t, target.finds, "find handles are closed")
+}
+
+func TestFSReadDirError(t *testing.T) {
+	target := newFakeTarget(map[string]string{`C:\Data\a.csv`: "a"})
+	target.findErr = fmt.Errorf("%w: connection lost", ads.ErrNotConnected)
+	fsys := New(target, `C:\Data\`)
+
+	_, err := fsys.ReadDir(".")
+	assert.ErrorIs(t, err, ads.ErrNotConnected)
+	assert.Empty(t, target.finds, "find handle is closed")
+	assert.Equal(t, 1, target.closes)
+}
+
+func TestFSWriteAndRemove(t *testing.T) {
+	target := newFakeTarget(map[string]string{`C:\Data\Recipes\`: ""})
+	fsys := New(target, `C:\Data`)
+
+	recipe := []byte(strings.Repeat("x;1\n", 10000))
+	if err := fsys.WriteFile("Recipes/r1.csv", recipe); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Equal(t, recipe, target.files[`C:\Data\Recipes\r1.csv`])
+	assert.Empty(t, target.open)
+
+	entries, err := fsys.ReadDir("Recipes")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "r1.csv", entries[0].Name())
+
+	if err := fsys.Remove("Recipes/r1.csv"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	_, err = fsys.ReadFile("Recipes/r1.csv")
+	assert.True(t, errors.Is(err, fs.ErrNotExist), "got %v", err)
+	assert.True(t, errors.Is(fsys.Remove("Recipes/r1.csv"), fs.ErrNotExist))
+	assert.True(t, errors.Is(fsys.WriteFile("Missing/r1.csv", recipe), fs.ErrNotExist))
+	assert.True(t, errors.Is(fsys.Remove("../x"), fs.ErrInvalid))
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/binary"
 	"strings"
 	"unicode/utf16"
-	// "golang.org/x/text/encoding/charmap"
 )
 
 // Trims given PLC string until '\\0' is found (removes empty bytes from the end)
@@ -16,23 +15,19 @@ func TrimPlcString(str string) string {
 	return str
 }
 
-// Decodes provided []byte to PLC STRING
+// Decodes provided []byte to PLC STRING (UTF-8, older TwinCAT versions use
+// Windows-1252, see DecodeWindows1252)
 func DecodePlcStringBuffer(data []byte) string {
-	decoded := string(data)
-	// this will be needed for older versions of twincat
-	// decoded, _ = charmap.Windows1252.NewDecoder().String(string(data))
-	return TrimPlcString(decoded)
+	return TrimPlcString(string(data))
 }
 
-// Encodes provided string to []byte as PLC STRING
+// Encodes provided string to []byte as PLC STRING (UTF-8, older TwinCAT
+// versions use Windows-1252, see EncodeWindows1252)
 func EncodeStringToPlcStringBuffer(str string) []byte {
 	return []byte(str)
-	// this will be needed for older versions of twincat
-	// encoded, _ := charmap.Windows1252.NewEncoder().String(str)
-	// return []byte(encoded)
 }
 
-// Decodes provided []byte to PLC WSTRING using ucs2 encoding, also trims zeroes
+// Decodes provided []byte to PLC WSTRING using UTF-16LE encoding, also trims zeroes
 func DecodePlcWstringBuffer(data []byte) string {
 	u16 := make([]uint16, len(data)/2)
 	_ = binary.Read(bytes.NewReader(data), binary.LittleEndian, &u16)
@@ -40,7 +35,7 @@ func DecodePlcWstringBuffer(data []byte) string {
 	return TrimPlcString(str)
 }
 
-// Encodes provided string to []byte as PLC WSTRING using ucs2 encoding
+// Encodes provided string to []byte as PLC WSTRING using UTF-16LE encoding
 func EncodeStringToPlcWstringBuffer(str string) []byte {
 	u16 := utf16.Encode([]rune(str))
 	buf := new(bytes.Buffer)
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// windows1252High maps the bytes 0x80-0x9F of Windows-1252 to runes. All other
+// bytes map to the rune with the same value (ISO 8859-1). Zero entries are
+// undefined in Windows-1252 and decode as U+FFFD.
+var windows1252High = [32]rune{
+	0x20AC, 0, 0x201A, 0x0192, 0x201E, 0x2026, 0x2020, 0x2021,
+	0x02C6, 0x2030, 0x0160, 0x2039, 0x0152, 0, 0x017D, 0,
+	0, 0x2018, 0x2019, 0x201C, 0x201D, 0x2022, 0x2013, 0x2014,
+	0x02DC, 0x2122, 0x0161, 0x203A, 0x0153, 0, 0x017E, 0x0178,
+}
+
+// Decodes provided Windows-1252 bytes (STRING data of older TwinCAT versions)
+func DecodeWindows1252(data []byte) string {
+	var sb strings.Builder
+	sb.Grow(len(data))
+	for _, b := range data {
+		if b >= 0x80 && b < 0xA0 {
+			if r := windows1252High[b-0x80]; r != 0 {
+				sb.WriteRune(r)
+			} else {
+				sb.WriteRune('�')
+			}
+			continue
+		}
+		sb.WriteRune(rune(b))
+	}
+	return sb.String()
+}
+
+// Encodes provided string to Windows-1252 bytes, fails on characters that
+// Windows-1252 cannot represent
+func EncodeWindows1252(str string) ([]byte, error) {
+	encoded := make([]byte, 0, len(str))
+	for _, r := range str {
+		b, ok := windows1252Byte(r)
+		if !ok {
+			return nil, fmt.Errorf("character %q cannot be encoded as Windows-1252", r)
+		}
+		encoded = append(encoded, b)
+	}
+	return encoded, nil
+}
+
+// windows1252Byte returns the Windows-1252 byte of r.
+func windows1252Byte(r rune) (byte, bool) {
+	if r < 0x80 || (r >= 0xA0 && r <= 0xFF) {
+		return byte(r), true
+	}
+	for i, high := range windows1252High {
+		if high != 0 && high == r {
+			return byte(0x80 + i), true
+		}
+	}
+	return 0, false
+}